@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/languages"
+	"dev_profiler/internal/pipeline"
+	"dev_profiler/internal/services"
+)
+
+// Exit codes returned by the audit command
+const (
+	ExitOK           = 0
+	ExitAuditFailed  = 1
+	ExitUsage        = 2
+	ExitLLMFailed    = 3
+	ExitOutputFailed = 4
+	ExitCancelled    = 130
+)
+
+// auditOptions holds the parsed arguments of the audit command
+type auditOptions struct {
+	Username  string
+	OutputDir string
+	SkipLLM   bool
+	Verbose   bool
+	Config    *config.Config
+}
+
+// RunAudit runs a headless GitHub audit for a single user and returns the process exit code
+func RunAudit(args []string, stdout, stderr io.Writer) int {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to load config, using defaults: %v\n", err)
+		cfg = config.DefaultConfig()
+	}
+
+	opts, err := parseAuditArgs(args, cfg, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	githubService := services.NewGitHubService(opts.Config.GitHub)
+	openaiService := services.NewOpenAIService(opts.Config.OpenAI)
+
+	runLLM := !opts.SkipLLM && opts.Config.OpenAI.APIKey != ""
+	pipelineOpts := pipeline.Options{
+		Auditor:   githubService,
+		OutputDir: opts.OutputDir,
+		// The audit JSON is the primary output when there is no LLM report
+		SaveDebugJSON: opts.Config.GitHub.SaveDebugJSON || !runLLM,
+		OnEvent: func(event pipeline.Event) {
+			// Per-commit and per-file updates are only logged in verbose mode
+			if event.Audit != nil && !opts.Verbose && isItemProgress(event.Audit.Kind) {
+				return
+			}
+			if event.Err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", event.Message, event.Err)
+				return
+			}
+			fmt.Fprintln(stderr, event.Message)
+		},
+	}
+	if runLLM {
+		pipelineOpts.Analyzer = openaiService
+	}
+
+	// Interrupting the command cancels the audit in progress
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	outcome, err := pipeline.Run(ctx, opts.Username, pipelineOpts)
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(stderr, "Analysis cancelled")
+		return ExitCancelled
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		if outcome == nil {
+			return ExitAuditFailed
+		}
+		return ExitOutputFailed
+	}
+
+	usage := outcome.Audit.AnalysisSummary.APIUsage
+	fmt.Fprintf(stderr, "GitHub API calls: %d, %d from cache (%d of %d remaining)", usage.Calls, usage.CachedResponses, usage.RateLimitRemaining, usage.RateLimit)
+	if usage.RateLimitWaits > 0 {
+		fmt.Fprintf(stderr, ", waited %.0fs for rate limits", usage.RateLimitWaitTime)
+	}
+	fmt.Fprintln(stderr)
+
+	if outcome.DebugJSONErr != nil {
+		return ExitOutputFailed
+	}
+
+	// Keep the audit data when the LLM analysis fails so the run isn't wasted
+	if outcome.LLMErr != nil && outcome.DebugJSONPath == "" {
+		outcome.DebugJSONPath, err = pipeline.SaveDebugJSON(opts.OutputDir, opts.Username, outcome.AuditJSON)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitOutputFailed
+		}
+	}
+
+	if outcome.HasReport() {
+		fmt.Fprintln(stdout, outcome.ReportPath)
+	}
+	if outcome.DebugJSONPath != "" {
+		fmt.Fprintln(stdout, outcome.DebugJSONPath)
+	}
+
+	if outcome.LLMErr != nil {
+		return ExitLLMFailed
+	}
+	return ExitOK
+}
+
+// parseAuditArgs parses the audit command arguments and applies flag overrides on top of cfg
+func parseAuditArgs(args []string, cfg *config.Config, output io.Writer) (*auditOptions, error) {
+	opts := &auditOptions{Config: cfg}
+
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: dev_profiler audit [flags] <username>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&opts.OutputDir, "out", ".", "Directory to write the JSON and HTML outputs to")
+	fs.BoolVar(&opts.SkipLLM, "no-llm", false, "Skip the OpenAI analysis and write the audit JSON only")
+	fs.BoolVar(&opts.Verbose, "v", false, "Log every fetched commit and sampled file")
+
+	// GitHub configuration overrides
+	token := fs.String("token", "", "GitHub API token (defaults to the configured token or $GITHUB_TOKEN)")
+	fs.IntVar(&cfg.GitHub.SampledRepoCount, "repos", cfg.GitHub.SampledRepoCount, "Repositories to analyze")
+	fs.IntVar(&cfg.GitHub.CommitsPerRepo, "commits", cfg.GitHub.CommitsPerRepo, "Commits per repository")
+	fs.IntVar(&cfg.GitHub.SampleFileCount, "files", cfg.GitHub.SampleFileCount, "Files to sample per repository")
+	fs.IntVar(&cfg.GitHub.PullRequestCount, "pull-requests", cfg.GitHub.PullRequestCount, "Authored and reviewed pull requests to collect (0 disables)")
+	fs.IntVar(&cfg.GitHub.IssueCount, "issues", cfg.GitHub.IssueCount, "Opened and commented issues to collect (0 disables)")
+	fs.IntVar(&cfg.GitHub.ExternalRepoCount, "external-repos", cfg.GitHub.ExternalRepoCount, "Repositories owned by others to report contributions for (0 disables)")
+	fs.BoolVar(&cfg.GitHub.SampleExternalDiffs, "external-diffs", cfg.GitHub.SampleExternalDiffs, "Sample the user's patches from the top external repositories")
+	fs.StringVar(&cfg.GitHub.SampleSource, "sample-source", cfg.GitHub.SampleSource, "Code samples to analyze: files (whole files) or diffs (hunks from the user's commits)")
+	fs.IntVar(&cfg.GitHub.DiffBudgetChars, "diff-budget", cfg.GitHub.DiffBudgetChars, "Characters of diff hunks kept per repository")
+	fs.BoolVar(&cfg.GitHub.EstimateOwnership, "ownership", cfg.GitHub.EstimateOwnership, "Estimate how much of each sampled file the user wrote and prefer their files (use -ownership=false to disable)")
+	fs.StringVar(&cfg.GitHub.SamplingStrategy, "sampling", cfg.GitHub.SamplingStrategy, "File sampling strategy: "+strings.Join(config.SamplingStrategies, ", "))
+	fs.Func("language", "Map a file extension or name to a language, as .ext=Language (repeatable, an empty language stops treating the files as code)", func(value string) error {
+		key, name, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return fmt.Errorf("expected .ext=Language or filename=Language")
+		}
+		if cfg.GitHub.LanguageOverrides == nil {
+			cfg.GitHub.LanguageOverrides = make(map[string]string)
+		}
+		cfg.GitHub.LanguageOverrides[key] = name
+		return nil
+	})
+	fs.Func("exclude", "Glob pattern of paths never sampled, matched against the path and the file name (repeatable)", func(pattern string) error {
+		if err := languages.ValidatePatterns([]string{pattern}); err != nil {
+			return err
+		}
+		cfg.GitHub.ExcludedPaths = append(cfg.GitHub.ExcludedPaths, pattern)
+		return nil
+	})
+	fs.Func("alias", "Extra git author email or name of the user, for commits not linked to the account (repeatable)", func(alias string) error {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("expected an email address or name")
+		}
+		cfg.GitHub.AuthorAliases = append(cfg.GitHub.AuthorAliases, alias)
+		return nil
+	})
+	fs.IntVar(&cfg.GitHub.AnalysisYears, "years", cfg.GitHub.AnalysisYears, "Years of activity to consider")
+	fs.BoolVar(&cfg.GitHub.IncludePrivateRepo, "include-private", cfg.GitHub.IncludePrivateRepo, "Include private repositories")
+	fs.BoolVar(&cfg.GitHub.IncludeArchived, "include-archived", cfg.GitHub.IncludeArchived, "Analyze archived repositories (use -include-archived=false to skip them)")
+	fs.BoolVar(&cfg.GitHub.OwnedReposOnly, "owned-only", cfg.GitHub.OwnedReposOnly, "List only repositories the user owns, skipping those they collaborate on")
+	fs.IntVar(&cfg.GitHub.RandomSeed, "seed", cfg.GitHub.RandomSeed, "Random seed for file sampling")
+	fs.IntVar(&cfg.GitHub.RepoConcurrency, "repo-workers", cfg.GitHub.RepoConcurrency, "Repositories analyzed in parallel")
+	fs.IntVar(&cfg.GitHub.CommitConcurrency, "commit-workers", cfg.GitHub.CommitConcurrency, "Parallel commit detail requests per repository")
+	fs.IntVar(&cfg.GitHub.FileConcurrency, "file-workers", cfg.GitHub.FileConcurrency, "Parallel file content requests per repository")
+	fs.StringVar(&cfg.GitHub.CollectorBackend, "backend", cfg.GitHub.CollectorBackend, "GitHub API used for profile, repository and commit data: rest or graphql")
+	fs.BoolVar(&cfg.GitHub.CacheEnabled, "cache", cfg.GitHub.CacheEnabled, "Cache GitHub API responses on disk (use -cache=false to disable)")
+	fs.IntVar(&cfg.GitHub.CacheTTLHours, "cache-ttl", cfg.GitHub.CacheTTLHours, "Hours before cached responses are revalidated")
+	fs.BoolVar(&cfg.GitHub.SaveDebugJSON, "save-debug-json", cfg.GitHub.SaveDebugJSON, "Also write the raw audit JSON when an HTML report is generated")
+
+	// OpenAI configuration overrides
+	apiKey := fs.String("openai-key", "", "OpenAI API key (defaults to the configured key or $OPENAI_API_KEY)")
+	fs.StringVar(&cfg.OpenAI.Model, "model", cfg.OpenAI.Model, "OpenAI model to use for analysis")
+	systemPromptFile := fs.String("system-prompt-file", "", "File containing a custom system prompt")
+	htmlTemplateFile := fs.String("html-template-file", "", "File containing a custom HTML report template")
+	cssFile := fs.String("css-file", "", "File containing custom CSS styles for the report")
+
+	// Flags may appear both before and after the username
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return nil, fmt.Errorf("a GitHub username is required")
+	}
+	opts.Username = strings.TrimSpace(fs.Arg(0))
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if opts.Username == "" {
+		return nil, fmt.Errorf("a GitHub username is required")
+	}
+	if cfg.GitHub.CollectorBackend != config.CollectorREST && cfg.GitHub.CollectorBackend != config.CollectorGraphQL {
+		return nil, fmt.Errorf("unknown backend %q, expected %s or %s", cfg.GitHub.CollectorBackend, config.CollectorREST, config.CollectorGraphQL)
+	}
+	if cfg.GitHub.SampleSource != config.SampleSourceFiles && cfg.GitHub.SampleSource != config.SampleSourceDiffs {
+		return nil, fmt.Errorf("unknown sample source %q, expected %s or %s", cfg.GitHub.SampleSource, config.SampleSourceFiles, config.SampleSourceDiffs)
+	}
+	if !slices.Contains(config.SamplingStrategies, cfg.GitHub.SamplingStrategy) {
+		return nil, fmt.Errorf("unknown sampling strategy %q, expected one of %s", cfg.GitHub.SamplingStrategy, strings.Join(config.SamplingStrategies, ", "))
+	}
+
+	switch {
+	case *token != "":
+		cfg.GitHub.Token = *token
+	case cfg.GitHub.Token == "":
+		cfg.GitHub.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	switch {
+	case *apiKey != "":
+		cfg.OpenAI.APIKey = *apiKey
+	case cfg.OpenAI.APIKey == "":
+		cfg.OpenAI.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+
+	fileOverrides := []struct {
+		path   string
+		target *string
+	}{
+		{*systemPromptFile, &cfg.OpenAI.SystemPrompt},
+		{*htmlTemplateFile, &cfg.OpenAI.HTMLTemplate},
+		{*cssFile, &cfg.OpenAI.CSSStyles},
+	}
+	for _, override := range fileOverrides {
+		if override.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(override.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", override.path, err)
+		}
+		*override.target = string(data)
+	}
+
+	return opts, nil
+}
+
+// isItemProgress reports whether a progress event describes a single commit or file
+func isItemProgress(kind services.ProgressKind) bool {
+	return kind == services.ProgressCommits || kind == services.ProgressFiles
+}
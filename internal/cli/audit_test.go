@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestParseAuditArgsOverrides(t *testing.T) {
+	promptFile := filepath.Join(t.TempDir(), "prompt.md")
+	if err := os.WriteFile(promptFile, []byte("custom prompt"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt file: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	args := []string{
+		"-repos", "3",
+		"-commits", "7",
+		"octocat",
+		"-files", "2",
+		"-pull-requests", "8",
+		"-issues", "9",
+		"-external-repos", "4",
+		"-external-diffs",
+		"-sample-source", "diffs",
+		"-diff-budget", "1000",
+		"-ownership=false",
+		"-sampling", "entry_points",
+		"-language", ".tpl=Go",
+		"-language", ".sh=",
+		"-exclude", "legacy/*",
+		"-alias", "octo@personal.example",
+		"-alias", "Octo Cat",
+		"-years", "1",
+		"-include-private",
+		"-include-archived=false",
+		"-owned-only",
+		"-seed", "99",
+		"-repo-workers", "2",
+		"-commit-workers", "4",
+		"-file-workers", "6",
+		"-cache=false",
+		"-backend", "graphql",
+		"-cache-ttl", "2",
+		"-save-debug-json",
+		"-token", "gh-token",
+		"-openai-key", "oa-key",
+		"-model", "gpt-test",
+		"-system-prompt-file", promptFile,
+		"-out", "reports",
+	}
+
+	var output bytes.Buffer
+	opts, err := parseAuditArgs(args, cfg, &output)
+	if err != nil {
+		t.Fatalf("parseAuditArgs() failed: %v", err)
+	}
+
+	if opts.Username != "octocat" {
+		t.Errorf("Expected username octocat, got %q", opts.Username)
+	}
+	if opts.OutputDir != "reports" {
+		t.Errorf("Expected output dir reports, got %q", opts.OutputDir)
+	}
+
+	gh := opts.Config.GitHub
+	if gh.SampledRepoCount != 3 || gh.CommitsPerRepo != 7 || gh.SampleFileCount != 2 || gh.AnalysisYears != 1 {
+		t.Errorf("Numeric GitHub overrides not applied: %+v", gh)
+	}
+	if !gh.IncludePrivateRepo || gh.IncludeArchived || !gh.OwnedReposOnly || !gh.SaveDebugJSON {
+		t.Errorf("Boolean GitHub overrides not applied: %+v", gh)
+	}
+	if gh.RepoConcurrency != 2 || gh.CommitConcurrency != 4 || gh.FileConcurrency != 6 {
+		t.Errorf("Concurrency overrides not applied: %+v", gh)
+	}
+	if gh.CollectorBackend != config.CollectorGraphQL {
+		t.Errorf("Expected graphql backend, got %q", gh.CollectorBackend)
+	}
+	if gh.CacheEnabled || gh.CacheTTLHours != 2 {
+		t.Errorf("Cache overrides not applied: %+v", gh)
+	}
+	if gh.SampleSource != config.SampleSourceDiffs || gh.DiffBudgetChars != 1000 || gh.EstimateOwnership {
+		t.Errorf("Diff sampling overrides not applied: %+v", gh)
+	}
+	if gh.SamplingStrategy != config.SamplingEntryPoints {
+		t.Errorf("Sampling strategy override not applied: %q", gh.SamplingStrategy)
+	}
+	if gh.LanguageOverrides[".tpl"] != "Go" || gh.LanguageOverrides[".sh"] != "" || len(gh.LanguageOverrides) != 2 {
+		t.Errorf("Language overrides not applied: %v", gh.LanguageOverrides)
+	}
+	if len(gh.ExcludedPaths) != 1 || gh.ExcludedPaths[0] != "legacy/*" {
+		t.Errorf("Excluded paths not applied: %v", gh.ExcludedPaths)
+	}
+	if len(gh.AuthorAliases) != 2 || gh.AuthorAliases[0] != "octo@personal.example" || gh.AuthorAliases[1] != "Octo Cat" {
+		t.Errorf("Author aliases not applied: %v", gh.AuthorAliases)
+	}
+	if gh.PullRequestCount != 8 || gh.IssueCount != 9 || gh.ExternalRepoCount != 4 || !gh.SampleExternalDiffs {
+		t.Errorf("Activity overrides not applied: %+v", gh)
+	}
+	if gh.RandomSeed != 99 {
+		t.Errorf("Expected seed 99, got %d", gh.RandomSeed)
+	}
+	if gh.Token != "gh-token" {
+		t.Errorf("Expected token override, got %q", gh.Token)
+	}
+
+	oa := opts.Config.OpenAI
+	if oa.APIKey != "oa-key" || oa.Model != "gpt-test" {
+		t.Errorf("OpenAI overrides not applied: key=%q model=%q", oa.APIKey, oa.Model)
+	}
+	if oa.SystemPrompt != "custom prompt" {
+		t.Errorf("Expected system prompt from file, got %q", oa.SystemPrompt)
+	}
+}
+
+func TestParseAuditArgsKeepsConfigDefaults(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	cfg := config.DefaultConfig()
+	cfg.GitHub.Token = "saved-token"
+	cfg.GitHub.SampledRepoCount = 4
+
+	opts, err := parseAuditArgs([]string{"octocat"}, cfg, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseAuditArgs() failed: %v", err)
+	}
+
+	if opts.Config.GitHub.Token != "saved-token" {
+		t.Errorf("Saved token should be kept, got %q", opts.Config.GitHub.Token)
+	}
+	if opts.Config.GitHub.SampledRepoCount != 4 {
+		t.Errorf("Saved repo count should be kept, got %d", opts.Config.GitHub.SampledRepoCount)
+	}
+	if opts.OutputDir != "." {
+		t.Errorf("Expected default output dir, got %q", opts.OutputDir)
+	}
+}
+
+func TestParseAuditArgsEnvironmentCredentials(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	t.Setenv("OPENAI_API_KEY", "env-key")
+
+	opts, err := parseAuditArgs([]string{"octocat"}, config.DefaultConfig(), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseAuditArgs() failed: %v", err)
+	}
+
+	if opts.Config.GitHub.Token != "env-token" {
+		t.Errorf("Expected token from environment, got %q", opts.Config.GitHub.Token)
+	}
+	if opts.Config.OpenAI.APIKey != "env-key" {
+		t.Errorf("Expected API key from environment, got %q", opts.Config.OpenAI.APIKey)
+	}
+}
+
+func TestParseAuditArgsErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{"missing username", []string{}},
+		{"only flags", []string{"-repos", "2"}},
+		{"extra arguments", []string{"octocat", "other"}},
+		{"invalid number", []string{"-repos", "many", "octocat"}},
+		{"missing prompt file", []string{"-system-prompt-file", "/nonexistent/prompt.md", "octocat"}},
+		{"unknown backend", []string{"-backend", "soap", "octocat"}},
+		{"unknown sample source", []string{"-sample-source", "blame", "octocat"}},
+		{"unknown sampling strategy", []string{"-sampling", "smallest", "octocat"}},
+		{"malformed language override", []string{"-language", "tpl", "octocat"}},
+		{"malformed exclusion pattern", []string{"-exclude", "[abc", "octocat"}},
+		{"empty author alias", []string{"-alias", " ", "octocat"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseAuditArgs(tc.args, config.DefaultConfig(), &bytes.Buffer{})
+			if err == nil {
+				t.Errorf("parseAuditArgs(%v) should fail", tc.args)
+			}
+		})
+	}
+}
+
+func TestRunAuditUsageExitCode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	if code := RunAudit(nil, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+	}
+	if code := RunAudit([]string{"-h"}, &stdout, &stderr); code != ExitOK {
+		t.Errorf("Expected exit code %d for help, got %d", ExitOK, code)
+	}
+}
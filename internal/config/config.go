@@ -12,6 +12,7 @@ import (
 const (
 	ConfigDirName  = "dev_profiler"
 	ConfigFileName = "config.json"
+	CacheDirName   = "cache"
 )
 
 // Config holds application configuration
@@ -47,6 +48,15 @@ func GetConfigDir() (string, error) {
 	return filepath.Join(homeDir, ".config", ConfigDirName), nil
 }
 
+// GetCacheDir returns the path to the GitHub API response cache directory
+func GetCacheDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, CacheDirName), nil
+}
+
 // ConfigExists checks if a configuration file exists
 func ConfigExists() bool {
 	configPath, err := GetConfigPath()
@@ -75,7 +85,8 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
+	// Start from the defaults so settings missing from older config files keep their default values
+	config := *DefaultConfig()
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
@@ -139,13 +150,32 @@ func SaveConfig(config *Config) error {
 	// Create a copy of config for encryption
 	configCopy := *config
 	configCopy.GitHub = &GitHubConfig{
-		SampledRepoCount:   config.GitHub.SampledRepoCount,
-		CommitsPerRepo:     config.GitHub.CommitsPerRepo,
-		SampleFileCount:    config.GitHub.SampleFileCount,
-		AnalysisYears:      config.GitHub.AnalysisYears,
-		IncludePrivateRepo: config.GitHub.IncludePrivateRepo,
-		RandomSeed:         config.GitHub.RandomSeed,
-		SaveDebugJSON:      config.GitHub.SaveDebugJSON,
+		SampledRepoCount:    config.GitHub.SampledRepoCount,
+		CommitsPerRepo:      config.GitHub.CommitsPerRepo,
+		SampleFileCount:     config.GitHub.SampleFileCount,
+		AnalysisYears:       config.GitHub.AnalysisYears,
+		IncludePrivateRepo:  config.GitHub.IncludePrivateRepo,
+		IncludeArchived:     config.GitHub.IncludeArchived,
+		OwnedReposOnly:      config.GitHub.OwnedReposOnly,
+		RandomSeed:          config.GitHub.RandomSeed,
+		SaveDebugJSON:       config.GitHub.SaveDebugJSON,
+		RepoConcurrency:     config.GitHub.RepoConcurrency,
+		CommitConcurrency:   config.GitHub.CommitConcurrency,
+		FileConcurrency:     config.GitHub.FileConcurrency,
+		CacheEnabled:        config.GitHub.CacheEnabled,
+		CacheTTLHours:       config.GitHub.CacheTTLHours,
+		CollectorBackend:    config.GitHub.CollectorBackend,
+		PullRequestCount:    config.GitHub.PullRequestCount,
+		IssueCount:          config.GitHub.IssueCount,
+		ExternalRepoCount:   config.GitHub.ExternalRepoCount,
+		SampleExternalDiffs: config.GitHub.SampleExternalDiffs,
+		SampleSource:        config.GitHub.SampleSource,
+		DiffBudgetChars:     config.GitHub.DiffBudgetChars,
+		EstimateOwnership:   config.GitHub.EstimateOwnership,
+		SamplingStrategy:    config.GitHub.SamplingStrategy,
+		LanguageOverrides:   config.GitHub.LanguageOverrides,
+		ExcludedPaths:       config.GitHub.ExcludedPaths,
+		AuthorAliases:       config.GitHub.AuthorAliases,
 	}
 	configCopy.OpenAI = &OpenAIConfig{
 		Model:        config.OpenAI.Model,
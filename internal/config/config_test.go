@@ -385,3 +385,75 @@ func TestConfigJSONStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadConfigKeepsDefaultsForMissingFields(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tempDir)
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		t.Fatalf("GetConfigDir() failed: %v", err)
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+
+	// Config written by an older version without the concurrency settings
+	oldConfig := `{"github": {"sampled_repo_count": 7}, "openai": {"model": "gpt-4o"}}`
+	configPath, _ := GetConfigPath()
+	if err := os.WriteFile(configPath, []byte(oldConfig), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	defaults := DefaultGitHubConfig()
+	if cfg.GitHub.SampledRepoCount != 7 {
+		t.Errorf("Expected SampledRepoCount 7, got %d", cfg.GitHub.SampledRepoCount)
+	}
+	if cfg.GitHub.RepoConcurrency != defaults.RepoConcurrency {
+		t.Errorf("Expected default RepoConcurrency %d, got %d", defaults.RepoConcurrency, cfg.GitHub.RepoConcurrency)
+	}
+	if cfg.GitHub.CommitConcurrency != defaults.CommitConcurrency {
+		t.Errorf("Expected default CommitConcurrency %d, got %d", defaults.CommitConcurrency, cfg.GitHub.CommitConcurrency)
+	}
+	if cfg.GitHub.FileConcurrency != defaults.FileConcurrency {
+		t.Errorf("Expected default FileConcurrency %d, got %d", defaults.FileConcurrency, cfg.GitHub.FileConcurrency)
+	}
+	if cfg.GitHub.PullRequestCount != defaults.PullRequestCount {
+		t.Errorf("Expected default PullRequestCount %d, got %d", defaults.PullRequestCount, cfg.GitHub.PullRequestCount)
+	}
+	if cfg.GitHub.IssueCount != defaults.IssueCount {
+		t.Errorf("Expected default IssueCount %d, got %d", defaults.IssueCount, cfg.GitHub.IssueCount)
+	}
+	if cfg.GitHub.ExternalRepoCount != defaults.ExternalRepoCount {
+		t.Errorf("Expected default ExternalRepoCount %d, got %d", defaults.ExternalRepoCount, cfg.GitHub.ExternalRepoCount)
+	}
+	if !cfg.GitHub.EstimateOwnership {
+		t.Error("Expected ownership estimates to be enabled by default")
+	}
+	if !cfg.GitHub.IncludeArchived {
+		t.Error("Expected archived repositories to be included by default")
+	}
+	if cfg.GitHub.OwnedReposOnly {
+		t.Error("Expected repositories the user collaborates on to be listed by default")
+	}
+	if cfg.GitHub.SampleSource != SampleSourceFiles || cfg.GitHub.DiffBudgetChars != defaults.DiffBudgetChars {
+		t.Errorf("Expected default diff sampling settings, got %q and %d", cfg.GitHub.SampleSource, cfg.GitHub.DiffBudgetChars)
+	}
+	if cfg.GitHub.SamplingStrategy != SamplingRandom {
+		t.Errorf("Expected default sampling strategy %q, got %q", SamplingRandom, cfg.GitHub.SamplingStrategy)
+	}
+	if len(cfg.GitHub.LanguageOverrides) != 0 || len(cfg.GitHub.ExcludedPaths) != 0 {
+		t.Errorf("Expected no language overrides or exclusions by default, got %v and %v", cfg.GitHub.LanguageOverrides, cfg.GitHub.ExcludedPaths)
+	}
+	if len(cfg.GitHub.AuthorAliases) != 0 {
+		t.Errorf("Expected no author aliases by default, got %v", cfg.GitHub.AuthorAliases)
+	}
+}
@@ -1,27 +1,94 @@
 package config
 
+// Data collection backends for GitHubConfig.CollectorBackend
+const (
+	CollectorREST    = "rest"
+	CollectorGraphQL = "graphql"
+)
+
+// Sources of the code samples sent for analysis, see GitHubConfig.SampleSource
+const (
+	SampleSourceFiles = "files" // Whole files sampled from the repository
+	SampleSourceDiffs = "diffs" // Hunks from the patches of the user's commits
+)
+
+// File sampling strategies for GitHubConfig.SamplingStrategy
+const (
+	SamplingRandom      = "random"       // Uniform random sample
+	SamplingLargest     = "largest"      // Largest hand-written files first
+	SamplingFrequent    = "frequent"     // Files the user changed most often first
+	SamplingEntryPoints = "entry_points" // Entry points such as main.go or files under cmd/ first
+	SamplingLanguages   = "languages"    // One file per language in turn
+	SamplingDirectories = "directories"  // One file per top-level directory in turn
+)
+
+// SamplingStrategies lists the valid values of GitHubConfig.SamplingStrategy
+var SamplingStrategies = []string{
+	SamplingRandom,
+	SamplingLargest,
+	SamplingFrequent,
+	SamplingEntryPoints,
+	SamplingLanguages,
+	SamplingDirectories,
+}
+
 // GitHubConfig holds GitHub API configuration
 type GitHubConfig struct {
-	Token              string `json:"token"`
-	SampledRepoCount   int    `json:"sampled_repo_count"`
-	CommitsPerRepo     int    `json:"commits_per_repo"`
-	SampleFileCount    int    `json:"sample_file_count"`
-	AnalysisYears      int    `json:"analysis_years"`
-	IncludePrivateRepo bool   `json:"include_private_repos"`
-	RandomSeed         int    `json:"random_seed"`
-	SaveDebugJSON      bool   `json:"save_debug_json"`
+	Token               string            `json:"token"`
+	SampledRepoCount    int               `json:"sampled_repo_count"`
+	CommitsPerRepo      int               `json:"commits_per_repo"`
+	SampleFileCount     int               `json:"sample_file_count"`
+	AnalysisYears       int               `json:"analysis_years"`
+	IncludePrivateRepo  bool              `json:"include_private_repos"`
+	IncludeArchived     bool              `json:"include_archived_repos"` // Analyze archived repositories; disabled repositories are never analyzed
+	OwnedReposOnly      bool              `json:"owned_repos_only"`       // List only repositories the user owns, leaving out those they collaborate on or are a member of
+	RandomSeed          int               `json:"random_seed"`
+	SaveDebugJSON       bool              `json:"save_debug_json"`
+	RepoConcurrency     int               `json:"repo_concurrency"`             // Repositories analyzed in parallel
+	CommitConcurrency   int               `json:"commit_concurrency"`           // Commit details fetched in parallel per repository
+	FileConcurrency     int               `json:"file_concurrency"`             // File contents fetched in parallel per repository
+	CacheEnabled        bool              `json:"cache_enabled"`                // Cache GitHub API responses on disk
+	CacheTTLHours       int               `json:"cache_ttl_hours"`              // Age after which cached responses are revalidated
+	CollectorBackend    string            `json:"collector_backend"`            // CollectorREST or CollectorGraphQL
+	PullRequestCount    int               `json:"pull_request_count"`           // Authored and reviewed pull requests to collect, 0 disables
+	IssueCount          int               `json:"issue_count"`                  // Opened and commented issues to collect, 0 disables
+	ExternalRepoCount   int               `json:"external_repo_count"`          // Repositories owned by others to report contributions for, 0 disables
+	SampleExternalDiffs bool              `json:"sample_external_diffs"`        // Include the user's patches from the top external repositories
+	SampleSource        string            `json:"sample_source"`                // SampleSourceFiles or SampleSourceDiffs
+	DiffBudgetChars     int               `json:"diff_budget_chars"`            // Characters of diff hunks kept per repository
+	EstimateOwnership   bool              `json:"estimate_ownership"`           // Estimate how much of each sampled file the user wrote
+	SamplingStrategy    string            `json:"sampling_strategy"`            // One of SamplingStrategies, used to pick files when sampling whole files
+	LanguageOverrides   map[string]string `json:"language_overrides,omitempty"` // File extensions (".tpl") or names mapped to a language, "" stops treating them as code
+	ExcludedPaths       []string          `json:"excluded_paths,omitempty"`     // Glob patterns of paths never sampled, on top of vendored and generated files
+	AuthorAliases       []string          `json:"author_aliases,omitempty"`     // Extra git author emails or names of the user, for commits not linked to the account; names must also appear on linked commits
 }
 
 // DefaultGitHubConfig returns default configuration
 func DefaultGitHubConfig() *GitHubConfig {
 	return &GitHubConfig{
-		Token:              "",
-		SampledRepoCount:   10,
-		CommitsPerRepo:     50,
-		SampleFileCount:    3,
-		AnalysisYears:      5,
-		IncludePrivateRepo: false,
-		RandomSeed:         42,
-		SaveDebugJSON:      false,
+		Token:               "",
+		SampledRepoCount:    10,
+		CommitsPerRepo:      50,
+		SampleFileCount:     3,
+		AnalysisYears:       5,
+		IncludePrivateRepo:  false,
+		IncludeArchived:     true,
+		OwnedReposOnly:      false,
+		RandomSeed:          42,
+		SaveDebugJSON:       false,
+		RepoConcurrency:     3,
+		CommitConcurrency:   5,
+		FileConcurrency:     5,
+		CacheEnabled:        true,
+		CacheTTLHours:       24,
+		CollectorBackend:    CollectorREST,
+		PullRequestCount:    30,
+		IssueCount:          30,
+		ExternalRepoCount:   10,
+		SampleExternalDiffs: false,
+		SampleSource:        SampleSourceFiles,
+		DiffBudgetChars:     6000,
+		EstimateOwnership:   true,
+		SamplingStrategy:    SamplingRandom,
 	}
 }
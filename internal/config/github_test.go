@@ -40,7 +40,7 @@ func TestDefaultGitHubConfig(t *testing.T) {
 
 func TestGitHubConfigValidation(t *testing.T) {
 	cfg := DefaultGitHubConfig()
-	
+
 	// Test that all fields have sensible defaults
 	testCases := []struct {
 		name     string
@@ -53,8 +53,11 @@ func TestGitHubConfigValidation(t *testing.T) {
 		{"SampleFileCount", "SampleFileCount", cfg.SampleFileCount, 1},
 		{"AnalysisYears", "AnalysisYears", cfg.AnalysisYears, 1},
 		{"RandomSeed", "RandomSeed", cfg.RandomSeed, int64(0)},
+		{"RepoConcurrency", "RepoConcurrency", cfg.RepoConcurrency, 1},
+		{"CommitConcurrency", "CommitConcurrency", cfg.CommitConcurrency, 1},
+		{"FileConcurrency", "FileConcurrency", cfg.FileConcurrency, 1},
 	}
-	
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			switch v := tc.value.(type) {
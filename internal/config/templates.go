@@ -24,6 +24,19 @@ IMPORTANT: Return ONLY clean markdown content. Use proper markdown syntax:
 
 Ensure all markdown is well-formed and follows standard markdown conventions.
 
+Private repositories are only counted when private_repos_included is true in the repository statistics; otherwise show N/A for Private Repos.
+For forks, user_commits counts only the commits the user made in the fork; upstream_commits are the user's commits inherited from the upstream repository and must not be credited as fork work.
+analysis_summary.repo_outcomes explains why each selected repository was or wasn't analyzed (no user commits, empty repository, or an API error); mention excluded repositories and the reason instead of guessing.
+When diff_samples is present, the code samples are hunks from the user's own commits rather than whole files: judge the changes the user made, and quote representative hunks in the Representative Code section.
+Sampled files whose ownership was estimated carry ownership, the estimated share of their lines written by the user, and ownership_source, which says whether it came from blame or commit history; when ownership_source is absent, the share is unknown, so ignore ownership and don't assume the user wrote little of the file. Files marked low_ownership were mostly written by others and must not be the basis of the assessment.
+Each sampled file has metrics computed from its content (comment ratio, function count and length, cyclomatic complexity, nesting depth and duplication; method "ast" is exact, "tokens" an approximation), and each analyzed repository has a metrics summary; use them to back up statements about code quality and complexity with numbers.
+Each analyzed repository has tests detected from its whole file tree: test and code file counts, test_ratio (test code per non-test code), test directories, test frameworks declared in manifests, CI systems and whether CI runs tests (ci_runs_tests); has_tests marks sampled files that are tests. Base the Testing assessment on these rather than on test keywords in sampled code.
+Each commit has its additions and deletions, and commit_activity (overall) and each analyzed repository's activity give commits per week, active days, the longest daily streak, churn, the median commit size and histograms of commits per hour of day and per weekday (starting on Sunday) in the author's own time zone, with utc_offsets counting commits per UTC offset; when most offsets are +00:00 the commits may have been recorded in UTC rather than local time, so be careful reading the hours as working hours; describe working patterns and commit granularity with these numbers instead of guessing.
+commit_messages (overall and per analyzed repository) scores the user's commit messages from 0 to 100 on subject length, imperative mood, Conventional Commits format, a body, issue references and tidiness, with a score histogram and the share of messages with each trait; noise_ratio is the share of uninformative messages such as "wip" or "update". Use it to assess commit hygiene and quote a few representative messages rather than reading through all of them.
+Each commit records matched_by: "login" when GitHub linked it to the user's account, or "email" or "name" when it was attributed through one of the user's other git identities (matched_identity); all of them are the user's own work.
+Repositories carry pushed_at, the time of the last push, which unlike updated_at doesn't change when a repository is starred: use it for recency and the Last Push columns. Mark archived repositories as archived, and use topics, license, homepage, size_kb, forks_count, watchers and open_issues to describe how mature and used each project is.
+Each analyzed repository's technologies come from its dependency manifests (go.mod, package.json, requirements files, pyproject.toml, Cargo.toml, pom.xml, Gradle builds, Gemfile, composer.json): recognized frameworks and libraries with their category, the direct dependency counts and other notable libraries. The top-level technologies list every technology with the repositories using it, most used first; base statements about the frameworks, databases and tools the user works with on these rather than on imports seen in sampled code.
+
 Structure the report as follows:
 
 ## User Overview
@@ -37,7 +50,7 @@ Structure the report as follows:
 | Email         | {email}             |
 | GitHub Since  | {created_at}        |
 | Public Repos  | {public_repos}      |
-| Private Repos | N/A                 |
+| Private Repos | {private_repos}     |
 | Forked Repos  | {count}             |
 | Public Gists  | {public_gists}      |
 | Followers     | {followers}         |
@@ -59,14 +72,14 @@ Structure the report as follows:
 
 #### Original Repositories
 
-| Repository                                        | Stars | Description | Languages | Created | Last Updated |
-| ------------------------------------------------- | ----- | ----------- | --------- | ------- | ------------ |
+| Repository                                        | Stars | Description | Languages | Created | Last Push |
+| ------------------------------------------------- | ----- | ----------- | --------- | ------- | --------- |
 | [Include all original repositories as table rows] |
 
 #### Forked Repositories
 
-| Repository                                      | Source | User Commits | Stars | Languages | Last Updated |
-| ----------------------------------------------- | ------ | ------------ | ----- | --------- | ------------ |
+| Repository                                      | Source | User Commits | Stars | Languages | Last Push |
+| ----------------------------------------------- | ------ | ------------ | ----- | --------- | --------- |
 | [Include all forked repositories as table rows] |
 
 ## Technical Assessment
@@ -95,6 +108,24 @@ Include 2-3 key code snippets in code blocks with proper language specification
 - **Design Decisions**: Details
 - **Areas for Improvement**: Details
 
+### Collaboration
+
+Summarize pull_requests and code_reviews: how many pull requests the user opened, in which repositories, how many were merged, their typical size and the review feedback they received, and the tone and depth of the reviews they left for others. Write "No pull request activity found" when both lists are empty.
+
+| Repository | Pull Requests | Merged | Reviews Given |
+| ---------- | ------------- | ------ | ------------- |
+| [One row per repository with pull request activity] |
+
+Use issue_activity to describe communication: the bug reports, feature requests and questions the user opened in other projects, and the support answers they gave on other people's issues. List the most active repositories from issue_activity.repositories.
+
+### Open-Source Contributions
+
+List external_contributions in order of significance: repositories owned by others where the user has merged pull requests or commits. Treat substantial contributions to popular projects as strong evidence of skill even when the user's own repositories are small, and use any diff_samples as code examples.
+
+| Repository | Stars | Merged PRs | Commits |
+| ---------- | ----- | ---------- | ------- |
+| [One row per external contribution] |
+
 ## Experience Level Assessment
 
 ### 1. Technical Proficiency
@@ -2,22 +2,19 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
-	"time"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/dialog"
 
 	"dev_profiler/internal/config"
-	"dev_profiler/internal/dto"
+	"dev_profiler/internal/httpcache"
+	"dev_profiler/internal/pipeline"
 	"dev_profiler/internal/services"
 	"dev_profiler/internal/ui"
 	"dev_profiler/internal/utils"
@@ -32,6 +29,10 @@ type MainController struct {
 	githubService *services.GitHubService
 	openaiService *services.OpenAIService
 	config        *config.Config
+
+	// cancelAnalysis cancels the analysis in progress, nil when idle
+	cancelMu       sync.Mutex
+	cancelAnalysis context.CancelFunc
 }
 
 // NewMainController creates a new MainController instance
@@ -88,6 +89,11 @@ func (ctrl *MainController) setupCallbacks() {
 		ctrl.analyzeGitHubProfile()
 	})
 
+	// Cancel button callback
+	ctrl.ui.SetCancelButtonCallback(func() {
+		ctrl.cancelRunningAnalysis()
+	})
+
 	// Config button callback
 	ctrl.ui.SetConfigButtonCallback(func() {
 		ctrl.showConfigWindow()
@@ -112,8 +118,14 @@ func (ctrl *MainController) analyzeGitHubProfile() {
 		return
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl.cancelMu.Lock()
+	ctrl.cancelAnalysis = cancel
+	ctrl.cancelMu.Unlock()
+
 	// Disable analyze button and show progress
 	ctrl.ui.SetAnalyzeButtonEnabled(false)
+	ctrl.ui.SetCancelButtonEnabled(true)
 	ctrl.ui.ShowProgress()
 	ctrl.ui.SetStatus("Starting analysis...")
 	ctrl.ui.SetProgress(0.1)
@@ -121,89 +133,78 @@ func (ctrl *MainController) analyzeGitHubProfile() {
 	// Perform analysis in goroutine
 	go func() {
 		defer func() {
+			ctrl.cancelMu.Lock()
+			ctrl.cancelAnalysis = nil
+			ctrl.cancelMu.Unlock()
+			cancel()
+
 			ctrl.ui.HideProgress()
+			ctrl.ui.SetCancelButtonEnabled(false)
 			ctrl.ui.SetAnalyzeButtonEnabled(true)
 		}()
 
-		ctx := context.Background()
+		opts := pipeline.Options{
+			Auditor:       ctrl.githubService,
+			SaveDebugJSON: ctrl.config.GitHub.SaveDebugJSON,
+			OpenReport:    true,
+			OnEvent: func(event pipeline.Event) {
+				ctrl.ui.SetStatus(event.Message)
+				ctrl.ui.SetProgress(event.Progress)
+			},
+		}
 
-		// Update progress
-		ctrl.ui.SetStatus("Fetching user information...")
-		ctrl.ui.SetProgress(0.2)
+		// LLM analysis is only performed when OpenAI is configured
+		if ctrl.config.OpenAI.APIKey != "" {
+			opts.Analyzer = ctrl.openaiService
+		}
 
-		// Perform the audit
-		result, err := ctrl.githubService.PerformFullAudit(ctx, username)
+		outcome, err := pipeline.Run(ctx, username, opts)
+		if errors.Is(err, context.Canceled) {
+			// Partial results are discarded, keep whatever was displayed before
+			ctrl.ui.SetStatus("Analysis cancelled")
+			return
+		}
 		if err != nil {
 			ctrl.ui.SetStatus("Analysis failed")
-			dialog.ShowError(fmt.Errorf("analysis failed: %v", err), ctrl.window)
+			dialog.ShowError(err, ctrl.window)
 			return
 		}
 
-		ctrl.ui.SetProgress(0.7)
-		ctrl.ui.SetStatus("Generating JSON report...")
-
-		// Convert result to JSON for display
-		jsonData, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			ctrl.ui.SetStatus("Failed to generate report")
-			dialog.ShowError(fmt.Errorf("failed to generate report: %v", err), ctrl.window)
-			return
+		if outcome.DebugJSONErr != nil {
+			// Log error but don't stop the process
+			fmt.Printf("Warning: Failed to save debug JSON: %v\n", outcome.DebugJSONErr)
+		} else if outcome.DebugJSONPath != "" {
+			fmt.Printf("Debug JSON saved to: %s\n", outcome.DebugJSONPath)
 		}
 
-		// Save debug JSON if enabled
-		if ctrl.config.GitHub.SaveDebugJSON {
-			ctrl.ui.SetStatus("Saving debug JSON file...")
-			err = ctrl.saveDebugJSON(result, username)
-			if err != nil {
-				// Log error but don't stop the process
-				fmt.Printf("Warning: Failed to save debug JSON: %v\n", err)
-			}
+		if outcome.LLMErr != nil {
+			// Show warning but continue with JSON report
+			dialog.ShowInformation("LLM Analysis Failed", fmt.Sprintf("OpenAI analysis failed: %v\n\nShowing JSON report instead.", outcome.LLMErr), ctrl.window)
 		}
 
-		// Check if OpenAI is configured for LLM analysis
-		if ctrl.config.OpenAI.APIKey != "" {
-			ctrl.ui.SetProgress(0.8)
-			ctrl.ui.SetStatus("Performing LLM analysis...")
-
-			// Perform OpenAI analysis
-			markdownAnalysis, err := ctrl.openaiService.AnalyzeGitHubData(result)
-			if err != nil {
-				// Show warning but continue with JSON report
-				ctrl.ui.SetStatus("LLM analysis failed, showing JSON report")
-				dialog.ShowInformation("LLM Analysis Failed", fmt.Sprintf("OpenAI analysis failed: %v\n\nShowing JSON report instead.", err), ctrl.window)
-			} else {
-				ctrl.ui.SetProgress(0.9)
-				ctrl.ui.SetStatus("Converting markdown to HTML and generating report...")
-
-				// Convert markdown to HTML
-				htmlContent := ctrl.openaiService.ConvertMarkdownToHTML(markdownAnalysis, username)
-
-				// Save report
-				err = ctrl.saveHTMLReport(htmlContent, username)
-				if err != nil {
-					dialog.ShowError(fmt.Errorf("failed to save HTML report: %v", err), ctrl.window)
-					return
-				}
-
-				// Display success message in the UI (HTML content is saved to file)
-				ctrl.ui.SetProgress(1.0)
-				ctrl.ui.SetStatus("LLM analysis completed successfully - HTML report saved")
-				ctrl.ui.SetResults(fmt.Sprintf("**Analysis Complete!**\n\nHTML report generated and opened in browser.\n\n**Summary:** Professional technical assessment completed for user '%s'. The detailed report includes:\n\n- User profile overview\n- Repository analysis\n- Code quality assessment\n- Experience level mapping\n- Hiring recommendations\n\nThe full report has been saved and automatically opened in your default browser.", username))
-				return
-			}
+		if outcome.HasReport() {
+			// Display success message in the UI (HTML content is saved to file)
+			ctrl.ui.SetResults(fmt.Sprintf("**Analysis Complete!**\n\nHTML report generated and opened in browser.\n\n**Summary:** Professional technical assessment completed for user '%s'. The detailed report includes:\n\n- User profile overview\n- Repository analysis\n- Code quality assessment\n- Experience level mapping\n- Hiring recommendations\n\nThe full report has been saved and automatically opened in your default browser.", username))
+			return
 		}
 
 		// Update UI with JSON results (fallback or no OpenAI)
-		ctrl.ui.SetProgress(1.0)
-		if ctrl.config.OpenAI.APIKey == "" {
-			ctrl.ui.SetStatus("Analysis completed - Configure OpenAI for LLM analysis")
-		} else {
-			ctrl.ui.SetStatus("Analysis completed with JSON report")
-		}
-		ctrl.ui.SetResults(string(jsonData))
+		ctrl.ui.SetResults(string(outcome.AuditJSON))
 	}()
 }
 
+// cancelRunningAnalysis cancels the analysis in progress, if any
+func (ctrl *MainController) cancelRunningAnalysis() {
+	ctrl.cancelMu.Lock()
+	defer ctrl.cancelMu.Unlock()
+
+	if ctrl.cancelAnalysis != nil {
+		ctrl.cancelAnalysis()
+		ctrl.ui.SetCancelButtonEnabled(false)
+		ctrl.ui.SetStatus("Cancelling analysis...")
+	}
+}
+
 // showConfigWindow shows the configuration dialog
 func (ctrl *MainController) showConfigWindow() {
 	// Create config UI
@@ -225,6 +226,8 @@ func (ctrl *MainController) showConfigWindow() {
 		configDialog.Hide()
 	})
 
+	ctrl.configUI.SetClearCacheButtonCallback(ctrl.clearCache)
+
 	configDialog.Show()
 }
 
@@ -243,15 +246,15 @@ func (ctrl *MainController) showFirstRunConfigWindow() {
 	ctrl.configUI.SetSaveButtonCallback(func() {
 		ctrl.saveConfigFromDialog()
 		configDialog.Hide()
-		
+
 		// Show welcome message after saving configuration
-		dialog.ShowInformation("Setup Complete", 
+		dialog.ShowInformation("Setup Complete",
 			"Welcome to GitHub Developer Profiler!\n\n"+
-			"Your configuration has been saved. You can now:\n"+
-			"• Enter a GitHub username to analyze\n"+
-			"• Configure additional settings anytime via the Config button\n"+
-			"• Generate professional developer assessments\n\n"+
-			"Get started by entering a GitHub username above!", ctrl.window)
+				"Your configuration has been saved. You can now:\n"+
+				"• Enter a GitHub username to analyze\n"+
+				"• Configure additional settings anytime via the Config button\n"+
+				"• Generate professional developer assessments\n\n"+
+				"Get started by entering a GitHub username above!", ctrl.window)
 	})
 
 	ctrl.configUI.SetCancelButtonCallback(func() {
@@ -259,20 +262,22 @@ func (ctrl *MainController) showFirstRunConfigWindow() {
 		defaultConfig := config.DefaultConfig()
 		ctrl.config = defaultConfig
 		config.SaveConfig(defaultConfig)
-		
+
 		// Update services with default config
 		ctrl.githubService = services.NewGitHubService(ctrl.config.GitHub)
 		ctrl.openaiService = services.NewOpenAIService(ctrl.config.OpenAI)
-		
+
 		configDialog.Hide()
-		
+
 		// Show information about skipping setup
-		dialog.ShowInformation("Setup Skipped", 
+		dialog.ShowInformation("Setup Skipped",
 			"Default configuration has been saved.\n\n"+
-			"Note: You'll need to configure your GitHub token and OpenAI API key "+
-			"via the Config button to perform analyses.", ctrl.window)
+				"Note: You'll need to configure your GitHub token and OpenAI API key "+
+				"via the Config button to perform analyses.", ctrl.window)
 	})
 
+	ctrl.configUI.SetClearCacheButtonCallback(ctrl.clearCache)
+
 	configDialog.Show()
 }
 
@@ -302,83 +307,23 @@ func (ctrl *MainController) saveConfigFromDialog() {
 	dialog.ShowInformation("Configuration Saved", "Configuration has been saved successfully.", ctrl.window)
 }
 
-// saveHTMLReport saves the HTML report to a file and opens it in browser
-func (ctrl *MainController) saveHTMLReport(htmlContent, username string) error {
-	// Create reports directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
-	}
-	
-	reportsDir := filepath.Join(homeDir, "github_reports")
-	if err := os.MkdirAll(reportsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create reports directory: %w", err)
-	}
-	
-	// Generate filename with timestamp
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := fmt.Sprintf("%s_github_assessment_%s.html", username, timestamp)
-	filePath := filepath.Join(reportsDir, filename)
-	
-	// Write HTML file
-	if err := os.WriteFile(filePath, []byte(htmlContent), 0644); err != nil {
-		return fmt.Errorf("failed to write HTML report: %w", err)
-	}
-	
-	// Try to open in system browser
-	go func() {
-		var cmd *exec.Cmd
-		switch runtime.GOOS {
-		case "linux":
-			cmd = exec.Command("xdg-open", filePath)
-		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", filePath)
-		case "darwin":
-			cmd = exec.Command("open", filePath)
-		default:
-			return // Unsupported OS
-		}
-		
-		if err := cmd.Run(); err != nil {
-			// Silently fail - user can manually open the file
+// clearCache deletes the cached GitHub API responses after confirmation
+func (ctrl *MainController) clearCache() {
+	dialog.ShowConfirm("Clear Cache", "Delete all cached GitHub API responses?\n\nThe next analysis will download everything again.", func(confirmed bool) {
+		if !confirmed {
 			return
 		}
-	}()
-	
-	return nil
-}
 
-// saveDebugJSON saves the raw GitHub audit data to a JSON file for debugging
-func (ctrl *MainController) saveDebugJSON(result *dto.AuditResult, username string) error {
-	// Get user home directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
-	}
-	
-	reportsDir := filepath.Join(homeDir, "github_reports")
-	if err := os.MkdirAll(reportsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create reports directory: %w", err)
-	}
-	
-	// Generate filename with timestamp
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := fmt.Sprintf("%s_github_audit_debug_%s.json", username, timestamp)
-	filePath := filepath.Join(reportsDir, filename)
-	
-	// Convert result to JSON with pretty formatting
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal audit data to JSON: %w", err)
-	}
-	
-	// Write JSON file
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write debug JSON file: %w", err)
-	}
-	
-	fmt.Printf("Debug JSON saved to: %s\n", filePath)
-	return nil
+		cacheDir, err := config.GetCacheDir()
+		if err == nil {
+			err = httpcache.Clear(cacheDir)
+		}
+		if err != nil {
+			dialog.ShowError(err, ctrl.window)
+			return
+		}
+		dialog.ShowInformation("Cache Cleared", "Cached GitHub API responses have been deleted.", ctrl.window)
+	}, ctrl.window)
 }
 
 // saveResults saves the analysis results to a file
@@ -26,57 +26,301 @@ type Repository struct {
 	Name            string            `json:"name"`
 	Description     string            `json:"description"`
 	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
+	UpdatedAt       time.Time         `json:"updated_at"` // Also changes when the repository is starred or its settings change
+	PushedAt        time.Time         `json:"pushed_at"`  // Last push to any branch
 	Stars           int               `json:"stars"`
+	Watchers        int               `json:"watchers"`
+	ForksCount      int               `json:"forks_count"`
+	OpenIssues      int               `json:"open_issues"` // Open issues and pull requests
+	SizeKB          int               `json:"size_kb"`
+	Topics          []string          `json:"topics,omitempty"`
+	License         string            `json:"license,omitempty"` // SPDX identifier such as MIT
+	Homepage        string            `json:"homepage,omitempty"`
+	DefaultBranch   string            `json:"default_branch,omitempty"`
 	Fork            bool              `json:"fork"`
+	Private         bool              `json:"private"`
+	Archived        bool              `json:"archived,omitempty"`
+	Disabled        bool              `json:"disabled,omitempty"`
 	ForkSource      string            `json:"fork_source,omitempty"`
-	UserCommits     int               `json:"user_commits,omitempty"`
+	UserCommits     int               `json:"user_commits,omitempty"`     // For forks, commits made in the fork only; counted only in repositories that may be analyzed
+	UpstreamCommits int               `json:"upstream_commits,omitempty"` // User commits a fork inherited from upstream
 	LanguagesUsed   []string          `json:"languages_used"`
 	FileCount       int               `json:"file_count"`
 	CommitCount     int               `json:"commit_count"`
 	IncludeAnalysis bool              `json:"include_in_analysis"`
 	IsSignificant   bool              `json:"is_significant,omitempty"`
+	Metrics         *RepoMetrics      `json:"metrics,omitempty"`         // Summary of the sampled files' static metrics
+	Tests           *RepoTests        `json:"tests,omitempty"`           // Tests found in the repository tree
+	Activity        *CommitActivity   `json:"activity,omitempty"`        // Statistics of the user's commits
+	CommitMessages  *MessageQuality   `json:"commit_messages,omitempty"` // Quality of the user's commit messages
+	Technologies    *RepoTechnologies `json:"technologies,omitempty"`    // Dependencies declared in the repository's manifests
 }
 
 // RepoStatistics holds repository statistics
 type RepoStatistics struct {
-	TotalRepos      int `json:"total_repos"`
-	OriginalRepos   int `json:"original_repos"`
-	ForkedRepos     int `json:"forked_repos"`
-	SignificantForks int `json:"significant_forks"`
-	TotalStars      int `json:"total_stars"`
-	AnalysisRepos   int `json:"analysis_repos"`
+	TotalRepos           int  `json:"total_repos"`
+	OriginalRepos        int  `json:"original_repos"`
+	ForkedRepos          int  `json:"forked_repos"`
+	SignificantForks     int  `json:"significant_forks"`
+	PrivateRepos         int  `json:"private_repos"`
+	ArchivedRepos        int  `json:"archived_repos"`
+	PrivateReposIncluded bool `json:"private_repos_included"` // False when private repositories could not be listed
+	TotalStars           int  `json:"total_stars"`
+	AnalysisRepos        int  `json:"analysis_repos"`
+	ReposWithTests       int  `json:"repos_with_tests"`   // Analyzed repositories containing test files
+	ReposTestedInCI      int  `json:"repos_tested_in_ci"` // Analyzed repositories whose CI configuration runs tests
 }
 
 // CommitDetail represents commit information
 type CommitDetail struct {
-	Repo      string    `json:"repo"`
-	SHA       string    `json:"sha"`
-	Message   string    `json:"message"`
-	Date      time.Time `json:"date"`
-	Author    string    `json:"author"`
-	FilesChanged []string `json:"files_changed"`
+	Repo            string        `json:"repo"`
+	SHA             string        `json:"sha"`
+	Message         string        `json:"message"`
+	Date            time.Time     `json:"date"`
+	Author          string        `json:"author"`
+	AuthorEmail     string        `json:"author_email,omitempty"`
+	MatchedBy       CommitMatch   `json:"matched_by"`                 // How the commit was attributed to the user
+	MatchedIdentity string        `json:"matched_identity,omitempty"` // The email or name matched, unless matched by login
+	FilesChanged    []string      `json:"files_changed"`
+	Additions       int           `json:"additions"`
+	Deletions       int           `json:"deletions"`
+	Patches         []*DiffSample `json:"-"` // Patches of the changed code files, kept when sampling diffs
+}
+
+// CommitMatch describes how a commit was attributed to the user
+type CommitMatch string
+
+const (
+	MatchedByLogin CommitMatch = "login" // GitHub linked the commit to the user's account
+	MatchedByEmail CommitMatch = "email" // The git author email is one of the user's identities
+	MatchedByName  CommitMatch = "name"  // The git author name is a configured alias also seen on the user's linked commits
+)
+
+// CommitActivity summarizes how much and when the user commits. Hours and days are in the UTC
+// offset recorded with each commit, the author's clock when git recorded it.
+type CommitActivity struct {
+	Commits          int            `json:"commits"`
+	FirstCommit      time.Time      `json:"first_commit"`
+	LastCommit       time.Time      `json:"last_commit"`
+	CommitsPerWeek   float64        `json:"commits_per_week"` // Over the weeks between the first and last commit
+	ActiveDays       int            `json:"active_days"`      // Days with at least one commit
+	LongestStreak    int            `json:"longest_streak"`   // Most consecutive days with commits
+	Additions        int            `json:"additions"`
+	Deletions        int            `json:"deletions"`
+	Churn            int            `json:"churn"`                 // Lines added plus lines deleted
+	MedianCommitSize int            `json:"median_commit_size"`    // Median of lines added plus deleted per commit
+	HourHistogram    [24]int        `json:"hour_histogram"`        // Commits per hour of the day
+	WeekdayHistogram [7]int         `json:"weekday_histogram"`     // Commits per weekday, starting on Sunday
+	UTCOffsets       map[string]int `json:"utc_offsets,omitempty"` // Commits per UTC offset, such as "-08:00"
+}
+
+// MessageQuality summarizes the quality of the user's commit messages. Ratios are shares of the
+// scored messages.
+type MessageQuality struct {
+	Messages          int     `json:"messages"`        // Scored messages
+	Generated         int     `json:"generated"`       // Merge and revert commits with generated messages, not scored
+	AvgScore          float64 `json:"avg_score"`       // 0 to 100
+	ScoreHistogram    [5]int  `json:"score_histogram"` // Messages scoring 0-19, 20-39, 40-59, 60-79 and 80-100
+	AvgSubjectLength  float64 `json:"avg_subject_length"`
+	LongSubjectRatio  float64 `json:"long_subject_ratio"` // Subjects over 72 characters
+	ImperativeRatio   float64 `json:"imperative_ratio"`   // Subjects starting with an imperative verb, such as "Add"
+	ConventionalRatio float64 `json:"conventional_ratio"` // Subjects following Conventional Commits, such as "fix(api): ..."
+	BodyRatio         float64 `json:"body_ratio"`         // Messages with a body explaining the change
+	IssueRefRatio     float64 `json:"issue_ref_ratio"`    // Messages referencing an issue or pull request
+	NoiseRatio        float64 `json:"noise_ratio"`        // Uninformative messages such as "wip", "fix" or "update"
+}
+
+// PullRequest represents a pull request authored by the user
+type PullRequest struct {
+	Repo           string    `json:"repo"` // owner/name
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	State          string    `json:"state"`
+	Merged         bool      `json:"merged"`
+	Additions      int       `json:"additions"`
+	Deletions      int       `json:"deletions"`
+	ChangedFiles   int       `json:"changed_files"`
+	ReviewComments int       `json:"review_comments"` // Review comments received on the diff
+	Comments       int       `json:"comments"`
+	CreatedAt      time.Time `json:"created_at"`
+	MergedAt       time.Time `json:"merged_at,omitempty"`
+	URL            string    `json:"url"`
+}
+
+// CodeReview represents a review the user left on someone else's pull request
+type CodeReview struct {
+	Repo              string    `json:"repo"` // owner/name
+	PullRequestNumber int       `json:"pull_request_number"`
+	PullRequestTitle  string    `json:"pull_request_title"`
+	PullRequestAuthor string    `json:"pull_request_author"`
+	State             string    `json:"state"` // APPROVED, CHANGES_REQUESTED or COMMENTED
+	Body              string    `json:"body,omitempty"`
+	SubmittedAt       time.Time `json:"submitted_at"`
+	URL               string    `json:"url"`
+}
+
+// IssueCategory classifies the user's participation in an issue
+type IssueCategory string
+
+const (
+	IssueBugReport      IssueCategory = "bug_report"
+	IssueFeatureRequest IssueCategory = "feature_request"
+	IssueQuestion       IssueCategory = "question"       // Opened by the user to ask for help
+	IssueSupportAnswer  IssueCategory = "support_answer" // The user commented on someone else's issue
+	IssueOther          IssueCategory = "other"
+)
+
+// Issue represents an issue in another owner's repository that the user opened or commented on
+type Issue struct {
+	Repo      string        `json:"repo"` // owner/name
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	State     string        `json:"state"`
+	Labels    []string      `json:"labels,omitempty"`
+	Author    string        `json:"author"`
+	Role      string        `json:"role"` // "author" or "commenter"
+	Category  IssueCategory `json:"category"`
+	Comments  int           `json:"comments"`
+	CreatedAt time.Time     `json:"created_at"`
+	URL       string        `json:"url"`
+}
+
+// IssueRepoSummary counts the user's issue participation in one external repository
+type IssueRepoSummary struct {
+	Repo            string `json:"repo"`
+	Opened          int    `json:"opened"`
+	CommentedOn     int    `json:"commented_on"`
+	BugReports      int    `json:"bug_reports"`
+	FeatureRequests int    `json:"feature_requests"`
+	Questions       int    `json:"questions"`
+	SupportAnswers  int    `json:"support_answers"`
+}
+
+// IssueActivity holds the issues the user opened or commented on outside their own repositories
+type IssueActivity struct {
+	Issues       []*Issue            `json:"issues"`
+	Repositories []*IssueRepoSummary `json:"repositories"` // Most active repositories first
+}
+
+// ExternalContribution summarizes the user's work in a repository owned by someone else
+type ExternalContribution struct {
+	Repo               string        `json:"repo"` // owner/name
+	Description        string        `json:"description,omitempty"`
+	Language           string        `json:"language,omitempty"`
+	Stars              int           `json:"stars"`
+	MergedPullRequests int           `json:"merged_pull_requests"`
+	Commits            int           `json:"commits"`      // Commits authored by the user on the default branch
+	Significance       float64       `json:"significance"` // Ranking score combining stars and contribution size
+	DiffSamples        []*DiffSample `json:"diff_samples,omitempty"`
+}
+
+// DiffSample holds the patch of a file changed in one of the user's commits
+type DiffSample struct {
+	Repo      string `json:"repo"`
+	SHA       string `json:"sha"`
+	Path      string `json:"path"`
+	Language  string `json:"language"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
 }
 
 // FileAnalysis represents code file analysis
 type FileAnalysis struct {
-	Repo         string `json:"repo"`
-	Path         string `json:"path"`
-	Language     string `json:"language"`
-	Size         int    `json:"size"`
-	Lines        int    `json:"lines"`
-	HasTests     bool   `json:"has_tests"`
-	Content      string `json:"content"`
+	Repo            string       `json:"repo"`
+	Path            string       `json:"path"`
+	Language        string       `json:"language"`
+	Size            int          `json:"size"`
+	Lines           int          `json:"lines"`
+	HasTests        bool         `json:"has_tests"` // A test by its path, or code using a test framework
+	Content         string       `json:"content"`
+	Ownership       *float64     `json:"ownership,omitempty"`        // Estimated share of the file written by the user, 0.0 to 1.0; nil when not estimated
+	OwnershipSource string       `json:"ownership_source,omitempty"` // "blame" or "commits", empty when not estimated
+	LowOwnership    bool         `json:"low_ownership,omitempty"`    // The user wrote little of this file
+	Metrics         *FileMetrics `json:"metrics,omitempty"`          // Static metrics computed locally from the content
+}
+
+// FileMetrics holds static metrics of a sampled file
+type FileMetrics struct {
+	CodeLines         int     `json:"code_lines"`
+	CommentLines      int     `json:"comment_lines"`
+	BlankLines        int     `json:"blank_lines"`
+	CommentRatio      float64 `json:"comment_ratio"` // Comment lines per code or comment line
+	Functions         int     `json:"functions"`
+	AvgFunctionLength float64 `json:"avg_function_length"` // Lines per function
+	MaxFunctionLength int     `json:"max_function_length"`
+	Complexity        int     `json:"complexity"`      // Cyclomatic complexity summed over the functions
+	MaxComplexity     int     `json:"max_complexity"`  // Cyclomatic complexity of the most complex function
+	MaxNesting        int     `json:"max_nesting"`     // Deepest nesting of control flow blocks
+	DuplicateLines    int     `json:"duplicate_lines"` // Code lines repeating an earlier run of lines in the file
+	DuplicationRatio  float64 `json:"duplication_ratio"`
+	Method            string  `json:"method"` // "ast" when parsed, "tokens" for the approximation
+}
+
+// RepoMetrics summarizes the static metrics of a repository's sampled files
+type RepoMetrics struct {
+	Files             int     `json:"files"`
+	CodeLines         int     `json:"code_lines"`
+	CommentRatio      float64 `json:"comment_ratio"`
+	Functions         int     `json:"functions"`
+	AvgFunctionLength float64 `json:"avg_function_length"`
+	MaxFunctionLength int     `json:"max_function_length"`
+	AvgComplexity     float64 `json:"avg_complexity"` // Average cyclomatic complexity per function
+	MaxComplexity     int     `json:"max_complexity"`
+	MaxNesting        int     `json:"max_nesting"`
+	DuplicationRatio  float64 `json:"duplication_ratio"`
+}
+
+// RepoTests describes the tests of a repository, detected from its file tree, test framework
+// manifests and CI configuration
+type RepoTests struct {
+	TestFiles   int      `json:"test_files"`
+	CodeFiles   int      `json:"code_files"` // Code files that aren't tests
+	TestRatio   float64  `json:"test_ratio"` // Test code per non-test code, by size when the tree reports sizes
+	TestDirs    []string `json:"test_dirs,omitempty"`
+	Frameworks  []string `json:"frameworks,omitempty"`
+	CISystems   []string `json:"ci_systems,omitempty"`
+	CIRunsTests bool     `json:"ci_runs_tests"` // A CI configuration runs a test command
+}
+
+// Technology is a framework or library recognized from the dependencies of a repository
+type Technology struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // Such as "web framework", "database" or "testing"
+}
+
+// RepoTechnologies is the technology inventory of a repository, built from its dependency manifests
+type RepoTechnologies struct {
+	Manifests       []string     `json:"manifests"`              // Paths of the manifests read
+	Ecosystems      []string     `json:"ecosystems"`             // Package registries such as npm or pypi
+	Dependencies    int          `json:"dependencies"`           // Direct dependencies, including development ones
+	DevDependencies int          `json:"dev_dependencies"`       // Direct dependencies only needed for development or tests
+	Technologies    []Technology `json:"technologies,omitempty"` // Recognized frameworks and libraries
+	Libraries       []string     `json:"libraries,omitempty"`    // Other direct runtime dependencies, up to a limit
+}
+
+// TechnologyUsage is a technology and the analyzed repositories using it
+type TechnologyUsage struct {
+	Technology
+	Repos []string `json:"repos"`
 }
 
 // AuditResult represents the complete audit result
 type AuditResult struct {
-	UserInfo        UserInfo         `json:"user_info"`
-	RepoStats       RepoStats        `json:"repo_stats"`
-	FileAnalysis    []*FileAnalysis  `json:"file_analysis"`
-	CommitDetails   []*CommitDetail  `json:"commit_details"`
-	AuditParameters config.GitHubConfig     `json:"audit_parameters"`
-	AnalysisSummary AnalysisSummary  `json:"analysis_summary"`
+	UserInfo              UserInfo                `json:"user_info"`
+	RepoStats             RepoStats               `json:"repo_stats"`
+	FileAnalysis          []*FileAnalysis         `json:"file_analysis"`
+	CommitDetails         []*CommitDetail         `json:"commit_details"`
+	CommitActivity        *CommitActivity         `json:"commit_activity,omitempty"` // Statistics of the user's commits across repositories
+	CommitMessages        *MessageQuality         `json:"commit_messages,omitempty"` // Quality of the user's commit messages across repositories
+	Technologies          []*TechnologyUsage      `json:"technologies,omitempty"`    // Technologies of the analyzed repositories, most used first
+	DiffSamples           []*DiffSample           `json:"diff_samples,omitempty"`    // Hunks from the user's commits when sampling diffs
+	PullRequests          []*PullRequest          `json:"pull_requests"`
+	CodeReviews           []*CodeReview           `json:"code_reviews"`
+	IssueActivity         IssueActivity           `json:"issue_activity"`
+	ExternalContributions []*ExternalContribution `json:"external_contributions"`
+	AuditParameters       config.GitHubConfig     `json:"audit_parameters"`
+	AnalysisSummary       AnalysisSummary         `json:"analysis_summary"`
 }
 
 // RepoStats holds repository statistics and lists
@@ -88,9 +332,44 @@ type RepoStats struct {
 
 // AnalysisSummary holds analysis summary information
 type AnalysisSummary struct {
-	ReposAnalyzedForCode       []string `json:"repos_analyzed_for_code"`
-	ReposWithErrors           []string `json:"repos_with_errors"`
-	ReposWithoutUserCommits   []string `json:"repos_without_user_commits"`
-	TotalReposAttempted       int      `json:"total_repos_attempted"`
-	SuccessfulAnalysisCount   int      `json:"successful_analysis_count"`
+	ReposAnalyzedForCode    []string       `json:"repos_analyzed_for_code"`
+	ReposWithErrors         []string       `json:"repos_with_errors"`
+	ReposWithoutUserCommits []string       `json:"repos_without_user_commits"`
+	TotalReposAttempted     int            `json:"total_repos_attempted"`
+	SuccessfulAnalysisCount int            `json:"successful_analysis_count"`
+	RepoOutcomes            []RepoOutcome  `json:"repo_outcomes"`               // One entry per repository selected for analysis
+	CollectionErrors        []string       `json:"collection_errors,omitempty"` // Data that couldn't be collected, such as pull requests or a repository's commit count
+	SampledLanguages        map[string]int `json:"sampled_languages,omitempty"` // Sampled files and diff hunks per detected language
+	APIUsage                APIUsage       `json:"api_usage"`
+}
+
+// RepoStatus describes what happened to a repository selected for analysis
+type RepoStatus string
+
+const (
+	RepoAnalyzed         RepoStatus = "analyzed"
+	RepoSkippedNoCommits RepoStatus = "skipped_no_commits" // The user has no commits in the repository
+	RepoSkippedEmpty     RepoStatus = "skipped_empty"      // The repository has no commits at all
+	RepoError            RepoStatus = "error"
+)
+
+// RepoOutcome records whether a repository was analyzed and why it was excluded otherwise
+type RepoOutcome struct {
+	Repo       string     `json:"repo"`
+	Status     RepoStatus `json:"status"`
+	Stage      string     `json:"stage,omitempty"` // Analysis step that failed: "commits" or "files"
+	Error      string     `json:"error,omitempty"`
+	HTTPStatus int        `json:"http_status,omitempty"`
+}
+
+// APIUsage records the GitHub API quota consumed by an audit
+type APIUsage struct {
+	Calls              int       `json:"calls"`            // GitHub API calls made, including retries
+	RateLimitWaits     int       `json:"rate_limit_waits"` // Pauses caused by primary or secondary rate limits
+	RateLimitWaitTime  float64   `json:"rate_limit_wait_seconds"`
+	RateLimit          int       `json:"rate_limit"` // Hourly quota reported by the last response
+	RateLimitRemaining int       `json:"rate_limit_remaining"`
+	RateLimitReset     time.Time `json:"rate_limit_reset,omitempty"`
+	CachedResponses    int       `json:"cached_responses"`   // Calls answered from the local response cache
+	CommitCountCalls   int       `json:"commit_count_calls"` // Calls, included in Calls, spent counting the user's commits per repository
 }
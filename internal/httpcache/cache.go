@@ -0,0 +1,201 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HeaderFromCache is set on responses served from the cache
+const HeaderFromCache = "X-From-Cache"
+
+// Values of HeaderFromCache
+const (
+	FromCacheFresh       = "fresh"       // Served without contacting the server
+	FromCacheRevalidated = "revalidated" // Confirmed unchanged by a conditional request
+)
+
+// Transport is an http.RoundTripper that stores successful GET responses on disk.
+// Entries younger than TTL are served directly, older entries are revalidated with
+// If-None-Match / If-Modified-Since so unchanged data is not downloaded again.
+type Transport struct {
+	Dir  string
+	TTL  time.Duration
+	Next http.RoundTripper // Defaults to http.DefaultTransport
+}
+
+// NewTransport creates a caching transport storing entries in dir
+func NewTransport(dir string, ttl time.Duration, next http.RoundTripper) *Transport {
+	return &Transport{Dir: dir, TTL: ttl, Next: next}
+}
+
+// RoundTrip serves the request from the cache when possible
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.next().RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	cached, storedAt, err := t.load(path, req)
+	if err != nil {
+		// Unreadable entries are treated as cache misses
+		cached = nil
+	}
+
+	if cached != nil && time.Since(storedAt) < t.TTL {
+		// Quota headers of a stored response are stale and must not be reported again
+		for name := range cached.Header {
+			if strings.HasPrefix(name, "X-Ratelimit-") {
+				cached.Header.Del(name)
+			}
+		}
+		cached.Header.Set(HeaderFromCache, FromCacheFresh)
+		return cached, nil
+	}
+
+	outgoing := req
+	if cached != nil {
+		outgoing = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			outgoing.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.next().RoundTrip(outgoing)
+	if err != nil {
+		if cached != nil {
+			cached.Body.Close()
+		}
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		for name, values := range resp.Header {
+			cached.Header[name] = values
+		}
+		// Refresh the stored headers and timestamp so the entry is fresh again
+		data, err := dumpResponse(cached)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached response: %w", err)
+		}
+		t.store(path, data)
+		cached.Header.Set(HeaderFromCache, FromCacheRevalidated)
+		return cached, nil
+	}
+	if cached != nil {
+		cached.Body.Close()
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	data, err := dumpResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	t.store(path, data)
+	return resp, nil
+}
+
+// Clear removes every cached response stored in dir
+func Clear(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// next returns the transport used for network requests
+func (t *Transport) next() http.RoundTripper {
+	if t.Next != nil {
+		return t.Next
+	}
+	return http.DefaultTransport
+}
+
+// entryPath returns the cache file for a request. The credentials are part of the key so
+// responses visible to one token are never served to another.
+func (t *Transport) entryPath(req *http.Request) string {
+	hash := sha256.New()
+	for _, part := range []string{req.URL.String(), req.Header.Get("Accept"), req.Header.Get("Authorization")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return filepath.Join(t.Dir, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// load reads a cached response and the time it was stored
+func (t *Transport) load(path string, req *http.Request) (*http.Response, time.Time, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return resp, info.ModTime(), nil
+}
+
+// dumpResponse serializes the whole response, replacing its body with an in-memory copy
+func dumpResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.TransferEncoding = nil
+	resp.Header.Del(HeaderFromCache)
+	raw, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// store writes a cache entry atomically so concurrent readers never see a partial file
+func (t *Transport) store(path string, data []byte) {
+	if err := os.MkdirAll(t.Dir, 0700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(t.Dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,172 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer serves a fixed body with an ETag and counts full and conditional requests
+func newTestServer(t *testing.T, body string) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+
+	var full, conditional int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&conditional, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &full, &conditional
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	return string(data), resp.Header.Get(HeaderFromCache)
+}
+
+func TestTransportServesFreshEntries(t *testing.T) {
+	server, full, conditional := newTestServer(t, "hello")
+	client := &http.Client{Transport: NewTransport(t.TempDir(), time.Hour, nil)}
+
+	body, source := get(t, client, server.URL+"/a")
+	if body != "hello" || source != "" {
+		t.Errorf("First request should reach the server, got body=%q source=%q", body, source)
+	}
+
+	body, source = get(t, client, server.URL+"/a")
+	if body != "hello" || source != FromCacheFresh {
+		t.Errorf("Second request should be served from the cache, got body=%q source=%q", body, source)
+	}
+
+	if *full != 1 || *conditional != 0 {
+		t.Errorf("Expected 1 full and 0 conditional requests, got %d and %d", *full, *conditional)
+	}
+}
+
+func TestTransportRevalidatesStaleEntries(t *testing.T) {
+	server, full, conditional := newTestServer(t, "hello")
+	client := &http.Client{Transport: NewTransport(t.TempDir(), 0, nil)}
+
+	get(t, client, server.URL+"/a")
+	for i := 0; i < 2; i++ {
+		body, source := get(t, client, server.URL+"/a")
+		if body != "hello" || source != FromCacheRevalidated {
+			t.Errorf("Stale entry should be revalidated, got body=%q source=%q", body, source)
+		}
+	}
+
+	if *full != 1 || *conditional != 2 {
+		t.Errorf("Expected 1 full and 2 conditional requests, got %d and %d", *full, *conditional)
+	}
+}
+
+func TestTransportSeparatesCredentials(t *testing.T) {
+	server, full, _ := newTestServer(t, "hello")
+	transport := NewTransport(t.TempDir(), time.Hour, nil)
+
+	for _, token := range []string{"token-a", "token-b", "token-a"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/a", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if *full != 2 {
+		t.Errorf("Each token should get its own cache entry, got %d full requests", *full)
+	}
+}
+
+func TestTransportSkipsErrorsAndOtherMethods(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(t.TempDir(), time.Hour, nil)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/missing")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		resp, err = client.Post(server.URL+"/post", "text/plain", nil)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if requests != 4 {
+		t.Errorf("Error responses and POST requests should not be cached, got %d requests", requests)
+	}
+}
+
+func TestTransportDropsStaleQuotaHeaders(t *testing.T) {
+	server, _, _ := newTestServer(t, "hello")
+	client := &http.Client{Transport: NewTransport(t.TempDir(), time.Hour, nil)}
+
+	get(t, client, server.URL+"/a")
+	resp, err := client.Get(server.URL + "/a")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "" {
+		t.Error("Fresh cache hits should not report stored quota headers")
+	}
+}
+
+func TestClear(t *testing.T) {
+	dir := t.TempDir()
+	server, full, _ := newTestServer(t, "hello")
+	client := &http.Client{Transport: NewTransport(dir, time.Hour, nil)}
+
+	get(t, client, server.URL+"/a")
+	if err := Clear(dir); err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Cache directory should be removed")
+	}
+
+	get(t, client, server.URL+"/a")
+	if *full != 2 {
+		t.Errorf("Request after Clear() should reach the server, got %d full requests", *full)
+	}
+}
@@ -0,0 +1,369 @@
+package languages
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Unknown is reported for files no language matches
+const Unknown = "Unknown"
+
+// Type classifies a language the way GitHub Linguist does
+type Type string
+
+const (
+	Programming Type = "programming" // Code worth sampling
+	Markup      Type = "markup"
+	Data        Type = "data"
+	Prose       Type = "prose"
+)
+
+// Language describes how files of a language are recognized
+type Language struct {
+	Name         string   `json:"name"`
+	Type         Type     `json:"type"`
+	Extensions   []string `json:"extensions,omitempty"`   // Lower case, with the leading dot
+	Filenames    []string `json:"filenames,omitempty"`    // Exact file names such as Dockerfile
+	Interpreters []string `json:"interpreters,omitempty"` // Shebang interpreters such as python3
+	Tests        []string `json:"tests,omitempty"`        // Path patterns of test files, such as _test\.go$
+	TestMarkers  []string `json:"test_markers,omitempty"` // Content patterns of test code, such as a test framework import
+
+	tests   []*regexp.Regexp
+	markers []*regexp.Regexp
+}
+
+// heuristic picks a language for an ambiguous extension from file content
+type heuristic struct {
+	Extension string `json:"extension"`
+	Rules     []struct {
+		Language string `json:"language"`
+		Pattern  string `json:"pattern"`
+	} `json:"rules"`
+}
+
+// table is the layout of the embedded languages.json
+type table struct {
+	Languages        []Language  `json:"languages"`
+	Heuristics       []heuristic `json:"heuristics"`
+	TestDirs         []string    `json:"test_dirs"`         // Path patterns of directories holding tests
+	Vendored         []string    `json:"vendored"`          // Path patterns of vendored dependencies and build output
+	Generated        []string    `json:"generated"`         // Path patterns of generated or minified files
+	GeneratedMarkers []string    `json:"generated_markers"` // Header comments marking generated files
+}
+
+//go:embed languages.json
+var embeddedTable []byte
+
+// rule is a compiled heuristic rule
+type rule struct {
+	language *Language
+	pattern  *regexp.Regexp
+}
+
+// Registry detects languages and classifies paths for filtering and reporting
+type Registry struct {
+	byName        map[string]*Language
+	byExtension   map[string]*Language
+	byFilename    map[string]*Language
+	byInterpreter map[string]*Language
+	heuristics    map[string][]rule
+	testDirs      []*regexp.Regexp
+	vendored      []*regexp.Regexp
+	generated     []*regexp.Regexp
+	markers       []string
+	excluded      []string
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default returns the registry built from the embedded table without user overrides
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = New(nil, nil)
+	})
+	return defaultRegistry
+}
+
+// New builds a registry from the embedded table. Overrides map file extensions (".tsx") or
+// names ("Jenkinsfile") to a language name; an empty name stops treating them as code.
+// Files matching an excluded glob pattern are never sampled.
+func New(overrides map[string]string, excluded []string) *Registry {
+	var t table
+	if err := json.Unmarshal(embeddedTable, &t); err != nil {
+		panic(fmt.Sprintf("invalid embedded language table: %v", err))
+	}
+
+	r := &Registry{
+		byName:        make(map[string]*Language),
+		byExtension:   make(map[string]*Language),
+		byFilename:    make(map[string]*Language),
+		byInterpreter: make(map[string]*Language),
+		heuristics:    make(map[string][]rule),
+		markers:       t.GeneratedMarkers,
+		excluded:      excluded,
+	}
+	for i := range t.Languages {
+		r.add(&t.Languages[i])
+	}
+	for _, h := range t.Heuristics {
+		for _, hr := range h.Rules {
+			r.heuristics[h.Extension] = append(r.heuristics[h.Extension], rule{
+				language: r.byName[hr.Language],
+				pattern:  regexp.MustCompile("(?m)" + hr.Pattern),
+			})
+		}
+	}
+	for _, pattern := range t.TestDirs {
+		r.testDirs = append(r.testDirs, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range t.Vendored {
+		r.vendored = append(r.vendored, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range t.Generated {
+		r.generated = append(r.generated, regexp.MustCompile(pattern))
+	}
+
+	for key, name := range overrides {
+		r.override(key, name)
+	}
+	return r
+}
+
+// add registers a language, keeping the first language listed for a shared extension
+func (r *Registry) add(lang *Language) {
+	for _, pattern := range lang.Tests {
+		lang.tests = append(lang.tests, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range lang.TestMarkers {
+		lang.markers = append(lang.markers, regexp.MustCompile("(?m)"+pattern))
+	}
+
+	r.byName[lang.Name] = lang
+	for _, ext := range lang.Extensions {
+		if _, exists := r.byExtension[ext]; !exists {
+			r.byExtension[ext] = lang
+		}
+	}
+	for _, name := range lang.Filenames {
+		r.byFilename[name] = lang
+	}
+	for _, interpreter := range lang.Interpreters {
+		r.byInterpreter[interpreter] = lang
+	}
+}
+
+// override maps an extension or file name to a language, creating unknown languages as
+// programming languages
+func (r *Registry) override(key, name string) {
+	var lang *Language
+	if name == "" {
+		lang = &Language{Name: Unknown, Type: Data}
+	} else if lang = r.byName[name]; lang == nil {
+		lang = &Language{Name: name, Type: Programming}
+		r.byName[name] = lang
+	}
+
+	if strings.HasPrefix(key, ".") {
+		ext := strings.ToLower(key)
+		r.byExtension[ext] = lang
+		delete(r.heuristics, ext)
+	} else {
+		r.byFilename[key] = lang
+	}
+}
+
+// lookup finds the language of a path from its file name or extension
+func (r *Registry) lookup(filePath string) *Language {
+	base := path.Base(filePath)
+	if lang, ok := r.byFilename[base]; ok {
+		return lang
+	}
+	return r.byExtension[strings.ToLower(path.Ext(base))]
+}
+
+// Lookup returns the language with the given name
+func (r *Registry) Lookup(name string) (*Language, bool) {
+	lang, ok := r.byName[name]
+	return lang, ok
+}
+
+// Detect returns the language name of a file. Content is optional; when given it resolves
+// ambiguous extensions such as .h and .m and identifies scripts by their shebang.
+func (r *Registry) Detect(filePath, content string) string {
+	lang := r.lookup(filePath)
+	if content != "" {
+		ext := strings.ToLower(path.Ext(filePath))
+		for _, rule := range r.heuristics[ext] {
+			if rule.language != nil && rule.pattern.MatchString(content) {
+				lang = rule.language
+				break
+			}
+		}
+		if lang == nil {
+			lang = r.interpreterLanguage(shebangInterpreter(content))
+		}
+	}
+
+	if lang == nil {
+		return Unknown
+	}
+	return lang.Name
+}
+
+// interpreterLanguage finds the language of an interpreter, ignoring a version suffix such as
+// the one in python3.12
+func (r *Registry) interpreterLanguage(interpreter string) *Language {
+	if lang, ok := r.byInterpreter[interpreter]; ok {
+		return lang
+	}
+	return r.byInterpreter[strings.TrimRight(interpreter, "0123456789.")]
+}
+
+// IsCode reports whether a path belongs to a programming language
+func (r *Registry) IsCode(filePath string) bool {
+	lang := r.lookup(filePath)
+	return lang != nil && lang.Type == Programming
+}
+
+// MayBeScript reports whether a path without an extension or a known file name, such as
+// bin/deploy, may be a script that only its shebang identifies. Hidden files and names in
+// capitals such as LICENSE or CODEOWNERS are never scripts.
+func (r *Registry) MayBeScript(filePath string) bool {
+	base := path.Base(filePath)
+	if path.Ext(base) != "" || strings.HasPrefix(base, ".") || strings.ToUpper(base) == base || r.lookup(filePath) != nil {
+		return false
+	}
+	return true
+}
+
+// IsCodeContent reports whether a file belongs to a programming language, identifying files
+// without a known extension by their shebang
+func (r *Registry) IsCodeContent(filePath, content string) bool {
+	lang, ok := r.byName[r.Detect(filePath, content)]
+	return ok && lang.Type == Programming
+}
+
+// IsTest reports whether a code file is a test, from its language's naming conventions or
+// because it is in a test directory
+func (r *Registry) IsTest(filePath string) bool {
+	lang := r.lookup(filePath)
+	if lang == nil || lang.Type != Programming {
+		return false
+	}
+	return r.TestDir(filePath) != "" || matchAny(lang.tests, filePath)
+}
+
+// TestDir returns the test directory holding a path, such as "server/tests", or "" when the
+// path isn't in one
+func (r *Registry) TestDir(filePath string) string {
+	for _, pattern := range r.testDirs {
+		if loc := pattern.FindStringIndex(filePath); loc != nil {
+			return filePath[:loc[1]-1]
+		}
+	}
+	return ""
+}
+
+// HasTestCode reports whether file content uses a test framework of its language
+func (r *Registry) HasTestCode(filePath, content string) bool {
+	lang, ok := r.byName[r.Detect(filePath, content)]
+	return ok && matchAny(lang.markers, content)
+}
+
+// matchAny reports whether any of the patterns matches s
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVendored reports whether a path holds vendored dependencies, build output or test fixtures
+func (r *Registry) IsVendored(filePath string) bool {
+	for _, pattern := range r.vendored {
+		if pattern.MatchString(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGenerated reports whether a file was generated, from its path and, when given, its header
+func (r *Registry) IsGenerated(filePath, content string) bool {
+	for _, pattern := range r.generated {
+		if pattern.MatchString(filePath) {
+			return true
+		}
+	}
+
+	header := content[:min(len(content), 1024)]
+	for _, marker := range r.markers {
+		if strings.Contains(header, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExcluded reports whether a path is vendored, generated or matches a user exclusion pattern.
+// Patterns are matched against the whole path and against the file name.
+func (r *Registry) IsExcluded(filePath string) bool {
+	if r.IsVendored(filePath) || r.IsGenerated(filePath, "") {
+		return true
+	}
+	for _, pattern := range r.excluded {
+		if matched, _ := path.Match(pattern, filePath); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, path.Base(filePath)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePatterns checks that exclusion patterns are well-formed globs
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclusion pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// shebangInterpreter returns the interpreter named by a script's shebang line, so both
+// "#!/bin/bash" and "#!/usr/bin/env bash" give "bash"
+func shebangInterpreter(content string) string {
+	line, _, _ := strings.Cut(content, "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env options such as -S
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = path.Base(field)
+				break
+			}
+		}
+	}
+	return interpreter
+}
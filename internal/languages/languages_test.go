@@ -0,0 +1,365 @@
+package languages
+
+import (
+	"testing"
+)
+
+func TestEmbeddedTable(t *testing.T) {
+	r := Default()
+	for ext, rules := range r.heuristics {
+		for _, rule := range rules {
+			if rule.language == nil {
+				t.Errorf("Heuristic for %s names an unknown language", ext)
+			}
+		}
+	}
+	for _, name := range []string{"Go", "TSX", "Shell", "SQL", "Vue", "Dart", "Elixir", "Haskell", "HCL", "Dockerfile"} {
+		if _, ok := r.Lookup(name); !ok {
+			t.Errorf("Expected %s in the language table", name)
+		}
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"main.go", true},
+		{"script.py", true},
+		{"app.js", true},
+		{"app.ts", true},
+		{"component.tsx", true},
+		{"component.jsx", true},
+		{"service.java", true},
+		{"utils.cpp", true},
+		{"main.c", true},
+		{"app.cs", true},
+		{"model.rb", true},
+		{"controller.php", true},
+		{"main.rs", true},
+		{"app.kt", true},
+		{"app.swift", true},
+		{"app.scala", true},
+		{"script.r", true},
+		{"Analysis.R", true},
+		{"main.m", true},
+		{"header.h", true},
+		{"header.hpp", true},
+		{"utils.cc", true},
+		{"main.cxx", true},
+		{"deploy.sh", true},
+		{"schema.sql", true},
+		{"App.vue", true},
+		{"widget.dart", true},
+		{"server.ex", true},
+		{"Main.hs", true},
+		{"infra/main.tf", true},
+		{"Dockerfile", true},
+		{"docker/Dockerfile", true},
+		{"Makefile", true},
+		{"Gemfile", true},
+
+		// Markup, data and prose
+		{"README.md", false},
+		{"LICENSE", false},
+		{"image.png", false},
+		{"document.pdf", false},
+		{"data.json", false},
+		{"config.ini", false},
+		{"log.txt", false},
+		{".gitignore", false},
+		{".env", false},
+		{"style.css", false},
+		{"index.html", false},
+		{"config.yml", false},
+		{"docker-compose.yaml", false},
+		{"package.json", false},
+		{"requirements.txt", false},
+		{"pom.xml", false},
+		{"notebook.ipynb", false},
+
+		// Edge cases
+		{"", false},
+		{"file_without_extension", false},
+		{".hidden", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := r.IsCode(tc.path); got != tc.expected {
+				t.Errorf("IsCode(%q) = %v, expected %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMayBeScript(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"bin/deploy", true},
+		{"scripts/release", true},
+		{"configure", true},
+		{"main.go", false},
+		{"notes.txt", false},
+		{"Makefile", false},
+		{"Dockerfile", false},
+		{"LICENSE", false},
+		{"docs/CODEOWNERS", false},
+		{".envrc", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		if got := r.MayBeScript(tc.path); got != tc.expected {
+			t.Errorf("MayBeScript(%q) = %v, expected %v", tc.path, got, tc.expected)
+		}
+	}
+}
+
+func TestIsCodeContent(t *testing.T) {
+	testCases := []struct {
+		path     string
+		content  string
+		expected bool
+	}{
+		{"bin/deploy", "#!/usr/bin/env bash\nset -e\n", true},
+		{"scripts/release", "#!/usr/bin/python3\nimport sys\n", true},
+		{"bin/notes", "Remember to deploy\n", false},
+		{"main.go", "package main\n", true},
+		{"README.md", "#!/bin/sh\n", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		if got := r.IsCodeContent(tc.path, tc.content); got != tc.expected {
+			t.Errorf("IsCodeContent(%q) = %v, expected %v", tc.path, got, tc.expected)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		content  string
+		expected string
+	}{
+		{"go", "main.go", "", "Go"},
+		{"tsx", "component.tsx", "", "TSX"},
+		{"filename", "Dockerfile", "", "Dockerfile"},
+		{"markup", "style.css", "", "CSS"},
+		{"unknown extension", "unknown.xyz", "", Unknown},
+		{"empty path", "", "", Unknown},
+
+		// Ambiguous extensions fall back to the first language without content
+		{"header without content", "header.h", "", "C"},
+		{"c header", "util.h", "#include <stdio.h>\nint add(int a, int b);\n", "C"},
+		{"c++ header", "util.h", "#pragma once\nnamespace util {\nclass Parser;\n}\n", "C++"},
+		{"objective-c header", "View.h", "#import <UIKit/UIKit.h>\n@interface View : UIView\n@end\n", "Objective-C"},
+		{"objective-c source", "View.m", "#import \"View.h\"\n@implementation View\n@end\n", "Objective-C"},
+		{"matlab source", "solve.m", "function x = solve(A, b)\n  % Solve the system\n  x = A \\ b;\nend\n", "MATLAB"},
+		{"perl script", "tool.pl", "use strict;\nmy $x = 1;\n", "Perl"},
+		{"prolog rules", "family.pl", "parent(X, Y) :- father(X, Y).\n", "Prolog"},
+
+		// Shebangs identify scripts without an extension
+		{"bash shebang", "bin/deploy", "#!/bin/bash\nset -e\n", "Shell"},
+		{"env shebang", "bin/tool", "#!/usr/bin/env python3\nprint('hi')\n", "Python"},
+		{"versioned shebang", "bin/tool", "#!/usr/bin/python3.12\n", "Python"},
+		{"env option", "bin/tool", "#!/usr/bin/env -S node --no-warnings\n", "JavaScript"},
+		{"unknown shebang", "bin/tool", "#!/usr/bin/awk -f\n", Unknown},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.Detect(tc.path, tc.content); got != tc.expected {
+				t.Errorf("Detect(%q) = %q, expected %q", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"main.go", false},
+		{"internal/server/handler.go", false},
+		{"bin/deploy.sh", false},
+		{"vendor/github.com/pkg/errors/errors.go", true},
+		{"web/node_modules/react/index.js", true},
+		{"Pods/Alamofire/Source/Request.swift", true},
+		{"internal/parser/testdata/input.go", true},
+		{"static/js/jquery-3.6.0.min.js", true},
+		{"static/app.min.js", true},
+		{"api/service.pb.go", true},
+		{"proto/service_pb2.py", true},
+		{"models/user.g.dart", true},
+		{"internal/mocks/store_mock.go", true},
+		{"legacy/old.go", true},
+		{"db/schema.sql", true},
+	}
+
+	r := New(nil, []string{"legacy/*", "*.sql"})
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := r.IsExcluded(tc.path); got != tc.expected {
+				t.Errorf("IsExcluded(%q) = %v, expected %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedContent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"go generate header", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n", true},
+		{"generated annotation", "/**\n * @generated\n */\nexport const x = 1\n", true},
+		{"hand-written", "package main\n\nfunc main() {}\n", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.IsGenerated("main.go", tc.content); got != tc.expected {
+				t.Errorf("IsGenerated() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsTest(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"internal/server/handler_test.go", true},
+		{"internal/server/handler.go", false},
+		{"tests/test_api.py", true},
+		{"app/test_utils.py", true},
+		{"app/contest.py", false},
+		{"src/components/Button.test.tsx", true},
+		{"src/lib/format.spec.ts", true},
+		{"src/__tests__/format.js", true},
+		{"src/test/java/com/example/UserServiceTest.java", true},
+		{"src/main/java/com/example/UserService.java", false},
+		{"spec/models/user_spec.rb", true},
+		{"crates/core/tests/parse.rs", true},
+		{"crates/core/src/parse.rs", false},
+		{"test/fixtures.json", false},
+		{"docs/testing.md", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := r.IsTest(tc.path); got != tc.expected {
+				t.Errorf("IsTest(%q) = %v, expected %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTestDir(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"tests/test_api.py", "tests"},
+		{"server/spec/models/user_spec.rb", "server/spec"},
+		{"web/src/__tests__/app.js", "web/src/__tests__"},
+		{"internal/contest/main.go", ""},
+		{"main_test.go", ""},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := r.TestDir(tc.path); got != tc.expected {
+				t.Errorf("TestDir(%q) = %q, expected %q", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHasTestCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		content  string
+		expected bool
+	}{
+		{"go test function", "check.go", "package check\n\nfunc TestParse(t *testing.T) {}\n", true},
+		{"go test word in code", "check.go", "package check\n\n// test the input\nfunc assert(ok bool) {}\n", false},
+		{"pytest import", "checks.py", "import pytest\n\ndef check():\n    pass\n", true},
+		{"python expect word", "client.py", "def expect_reply(sock):\n    return sock.recv()\n", false},
+		{"jest expectation", "sum.js", "expect(sum(1, 2)).toBe(3)\n", true},
+		{"rust test attribute", "lib.rs", "#[cfg(test)]\nmod tests {}\n", true},
+		{"junit annotation", "Parser.java", "class ParserCheck {\n  @Test\n  void parses() {}\n}\n", true},
+		{"markers of another language", "notes.md", "func TestParse(t *testing.T) {}\n", false},
+	}
+
+	r := Default()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.HasTestCode(tc.path, tc.content); got != tc.expected {
+				t.Errorf("HasTestCode(%q) = %v, expected %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	r := New(map[string]string{
+		".TPL":     "Go",
+		".sql":     "",
+		"Justfile": "Just",
+		".h":       "C++",
+	}, nil)
+
+	testCases := []struct {
+		path     string
+		content  string
+		language string
+		code     bool
+	}{
+		{"page.tpl", "", "Go", true},
+		{"schema.sql", "", Unknown, false},
+		{"Justfile", "", "Just", true},
+		{"View.h", "@interface View\n@end\n", "C++", true},
+		{"main.go", "", "Go", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := r.Detect(tc.path, tc.content); got != tc.language {
+				t.Errorf("Detect(%q) = %q, expected %q", tc.path, got, tc.language)
+			}
+			if got := r.IsCode(tc.path); got != tc.code {
+				t.Errorf("IsCode(%q) = %v, expected %v", tc.path, got, tc.code)
+			}
+		})
+	}
+
+	// Overrides don't change the shared default registry
+	if Default().Detect("page.tpl", "") != Unknown {
+		t.Error("Overrides should not leak into the default registry")
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := ValidatePatterns([]string{"legacy/*", "*.sql"}); err != nil {
+		t.Errorf("ValidatePatterns() failed for valid patterns: %v", err)
+	}
+	if err := ValidatePatterns([]string{"[unclosed"}); err == nil {
+		t.Error("ValidatePatterns() should reject malformed patterns")
+	}
+}
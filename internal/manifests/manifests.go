@@ -0,0 +1,399 @@
+package manifests
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ecosystems of the package registries dependencies are declared for
+const (
+	Go       = "go"
+	NPM      = "npm"
+	PyPI     = "pypi"
+	Cargo    = "cargo"
+	Maven    = "maven"
+	Gradle   = "gradle" // Gradle plugins such as org.springframework.boot
+	RubyGems = "rubygems"
+	Composer = "composer"
+)
+
+// Dependency is a direct dependency declared in a manifest
+type Dependency struct {
+	Name      string // Normalized package name, such as group:artifact for Maven
+	Ecosystem string
+	Dev       bool // Only needed for development or tests
+}
+
+// parser reads the dependencies declared in a manifest
+type parser func(filePath, content string) ([]Dependency, error)
+
+var (
+	requirementsFile = regexp.MustCompile(`^requirements[^/]*\.txt$`)
+	requirementName  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+	tomlTable        = regexp.MustCompile(`^\[\[?\s*([^\]]+?)\s*\]\]?`)
+	tomlKey          = regexp.MustCompile(`^["']?([A-Za-z0-9_.@/-]+?)["']?\s*=`)
+	quotedString     = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+	gradleDependency = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*(?:platform\(\s*|enforcedPlatform\(\s*)?["']([\w.-]+):([\w.-]+)`)
+	gradlePlugin     = regexp.MustCompile(`(?m)^\s*id\s*\(?\s*["']([\w.-]+)["']`)
+	gemDeclaration   = regexp.MustCompile(`^gem\s+["']([^"']+)["']`)
+	gemGroup         = regexp.MustCompile(`^group\b(.*)\bdo\b`)
+)
+
+// Gradle configurations that declare dependencies, mapped to whether they only serve development
+var gradleConfigurations = map[string]bool{
+	"implementation": false, "api": false, "compile": false, "compileOnly": false, "runtimeOnly": false,
+	"runtime": false, "annotationProcessor": false, "kapt": false, "ksp": false, "classpath": false,
+	"testImplementation": true, "testCompile": true, "testCompileOnly": true, "testRuntimeOnly": true,
+	"androidTestImplementation": true, "debugImplementation": true, "testAnnotationProcessor": true,
+}
+
+// parsers maps manifest file names to their parser
+var parsers = map[string]parser{
+	"go.mod":           parseGoMod,
+	"package.json":     parsePackageJSON,
+	"pyproject.toml":   parsePyproject,
+	"Cargo.toml":       parseCargo,
+	"pom.xml":          parsePom,
+	"build.gradle":     parseGradle,
+	"build.gradle.kts": parseGradle,
+	"Gemfile":          parseGemfile,
+	"composer.json":    parseComposer,
+}
+
+// lookup finds the parser of a manifest path
+func lookup(filePath string) parser {
+	base := path.Base(filePath)
+	if p, ok := parsers[base]; ok {
+		return p
+	}
+	if requirementsFile.MatchString(base) || (path.Base(path.Dir(filePath)) == "requirements" && path.Ext(base) == ".txt") {
+		return parseRequirements
+	}
+	return nil
+}
+
+// IsManifest reports whether a path is a dependency manifest that can be parsed
+func IsManifest(filePath string) bool {
+	return lookup(filePath) != nil
+}
+
+// Parse returns the direct dependencies declared in a manifest. Malformed JSON and XML manifests
+// return an error; other formats are read line by line, skipping what they don't understand.
+func Parse(filePath, content string) ([]Dependency, error) {
+	p := lookup(filePath)
+	if p == nil {
+		return nil, fmt.Errorf("unsupported manifest: %s", filePath)
+	}
+	return p(filePath, content)
+}
+
+// lines splits content into trimmed lines
+func lines(content string) []string {
+	var result []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		result = append(result, strings.TrimSpace(scanner.Text()))
+	}
+	return result
+}
+
+// parseGoMod reads the modules required by a go.mod, skipping indirect dependencies
+func parseGoMod(_, content string) ([]Dependency, error) {
+	var deps []Dependency
+	inBlock := false
+	for _, line := range lines(content) {
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case line == "require (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inBlock:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "//") || strings.Contains(line, "// indirect") {
+			continue
+		}
+		deps = append(deps, Dependency{Name: fields[0], Ecosystem: Go})
+	}
+	return deps, nil
+}
+
+// sortedDependencies returns the names of a JSON dependency object in sorted order
+func sortedDependencies(set map[string]any) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsePackageJSON reads the dependencies and development dependencies of a package.json
+func parsePackageJSON(_, content string) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies    map[string]any `json:"dependencies"`
+		DevDependencies map[string]any `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	var deps []Dependency
+	for _, name := range sortedDependencies(pkg.Dependencies) {
+		deps = append(deps, Dependency{Name: name, Ecosystem: NPM})
+	}
+	for _, name := range sortedDependencies(pkg.DevDependencies) {
+		deps = append(deps, Dependency{Name: name, Ecosystem: NPM, Dev: true})
+	}
+	return deps, nil
+}
+
+// parseComposer reads the packages required by a composer.json, skipping PHP itself and its extensions
+func parseComposer(_, content string) ([]Dependency, error) {
+	var pkg struct {
+		Require    map[string]any `json:"require"`
+		RequireDev map[string]any `json:"require-dev"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse composer.json: %w", err)
+	}
+
+	var deps []Dependency
+	add := func(set map[string]any, dev bool) {
+		for _, name := range sortedDependencies(set) {
+			// Platform packages such as php, ext-json and lib-curl aren't installed with Composer
+			if !strings.Contains(name, "/") {
+				continue
+			}
+			deps = append(deps, Dependency{Name: strings.ToLower(name), Ecosystem: Composer, Dev: dev})
+		}
+	}
+	add(pkg.Require, false)
+	add(pkg.RequireDev, true)
+	return deps, nil
+}
+
+// pythonPackage returns the normalized name of a Python requirement such as "Flask[async]>=2.0",
+// or an empty string if it names no package
+func pythonPackage(requirement string) string {
+	name := requirementName.FindString(strings.TrimSpace(requirement))
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// parseRequirements reads a pip requirements file. Files named for development, such as
+// requirements-dev.txt, declare development dependencies.
+func parseRequirements(filePath, content string) ([]Dependency, error) {
+	base := strings.ToLower(path.Base(filePath))
+	dev := strings.Contains(base, "dev") || strings.Contains(base, "test") || strings.Contains(base, "lint") || strings.Contains(base, "doc")
+
+	var deps []Dependency
+	for _, line := range lines(content) {
+		line, _, _ = strings.Cut(line, " #")
+		// Skip comments, pip options and includes, and requirements given only by URL or path
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") ||
+			(strings.Contains(line, "://") && !strings.Contains(line, " @ ")) || strings.HasPrefix(line, ".") {
+			continue
+		}
+		if name := pythonPackage(line); name != "" {
+			deps = append(deps, Dependency{Name: name, Ecosystem: PyPI, Dev: dev})
+		}
+	}
+	return deps, nil
+}
+
+// tomlLines walks the key-value pairs of a TOML document, calling fn with the table, the key and
+// the value. Each line of a multi-line array is passed as a value of the array's key. It understands
+// just enough TOML to read dependency tables.
+func tomlLines(content string, fn func(table, key, value string)) {
+	table, arrayKey := "", ""
+	for _, line := range lines(content) {
+		line, _, _ = strings.Cut(line, " #")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if arrayKey != "" {
+			fn(table, arrayKey, line)
+			if strings.HasSuffix(strings.TrimRight(line, ", "), "]") {
+				arrayKey = ""
+			}
+			continue
+		}
+		if match := tomlTable.FindStringSubmatch(line); match != nil {
+			table = strings.NewReplacer(`"`, "", "'", "").Replace(match[1])
+			continue
+		}
+
+		match := tomlKey.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		_, value, _ := strings.Cut(line, "=")
+		value = strings.TrimSpace(value)
+		fn(table, match[1], value)
+		if strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") {
+			arrayKey = match[1]
+		}
+	}
+}
+
+// quotedStrings returns the quoted strings of a TOML value
+func quotedStrings(value string) []string {
+	var values []string
+	for _, match := range quotedString.FindAllStringSubmatch(value, -1) {
+		values = append(values, match[1]+match[2])
+	}
+	return values
+}
+
+// parsePyproject reads the dependencies of a pyproject.toml declared under [project], in
+// dependency groups or for Poetry
+func parsePyproject(_, content string) ([]Dependency, error) {
+	var deps []Dependency
+	seen := make(map[string]bool)
+	add := func(name string, dev bool) {
+		if name != "" && name != "python" && !seen[name] {
+			seen[name] = true
+			deps = append(deps, Dependency{Name: name, Ecosystem: PyPI, Dev: dev})
+		}
+	}
+
+	tomlLines(content, func(table, key, value string) {
+		switch {
+		case table == "project" && key == "dependencies":
+			for _, requirement := range quotedStrings(value) {
+				add(pythonPackage(requirement), false)
+			}
+		case table == "project.optional-dependencies", table == "dependency-groups":
+			for _, requirement := range quotedStrings(value) {
+				add(pythonPackage(requirement), true)
+			}
+		case table == "tool.poetry.dependencies":
+			add(pythonPackage(key), false)
+		case table == "tool.poetry.dev-dependencies",
+			strings.HasPrefix(table, "tool.poetry.group.") && strings.HasSuffix(table, ".dependencies"):
+			add(pythonPackage(key), true)
+		}
+	})
+	return deps, nil
+}
+
+// parseCargo reads the dependencies of a Cargo.toml, including those of workspaces and targets
+func parseCargo(_, content string) ([]Dependency, error) {
+	var deps []Dependency
+	seen := make(map[string]bool)
+	add := func(name string, dev bool) {
+		if !seen[name] {
+			seen[name] = true
+			deps = append(deps, Dependency{Name: name, Ecosystem: Cargo, Dev: dev})
+		}
+	}
+
+	dependencyTable := func(table string) (kind string, ok bool) {
+		if strings.HasPrefix(table, "target.") {
+			table = table[strings.LastIndex(table, ".")+1:]
+		}
+		table = strings.TrimPrefix(table, "workspace.")
+		switch table {
+		case "dependencies", "dev-dependencies", "build-dependencies":
+			return table, true
+		}
+		return "", false
+	}
+
+	seenTables := make(map[string]bool)
+	tomlLines(content, func(table, key, _ string) {
+		if kind, ok := dependencyTable(table); ok {
+			add(key, kind == "dev-dependencies")
+			return
+		}
+		// Tables such as [dependencies.serde] declare a single dependency
+		parent, name, found := strings.Cut(table, ".")
+		if kind, ok := dependencyTable(parent); found && ok && !seenTables[table] {
+			seenTables[table] = true
+			add(name, kind == "dev-dependencies")
+		}
+	})
+	return deps, nil
+}
+
+// parsePom reads the dependencies of a Maven pom.xml as group:artifact. Entries of
+// dependencyManagement only pin versions, such as those imported from a BOM, and are left out.
+func parsePom(_, content string) ([]Dependency, error) {
+	type pomDependency struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Scope      string `xml:"scope"`
+	}
+	var pom struct {
+		Parent       pomDependency   `xml:"parent"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal([]byte(content), &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse pom.xml: %w", err)
+	}
+
+	var deps []Dependency
+	// A parent such as spring-boot-starter-parent often names the framework of the project
+	for _, dep := range append([]pomDependency{pom.Parent}, pom.Dependencies...) {
+		if dep.GroupID == "" || dep.ArtifactID == "" {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      strings.TrimSpace(dep.GroupID) + ":" + strings.TrimSpace(dep.ArtifactID),
+			Ecosystem: Maven,
+			Dev:       strings.TrimSpace(dep.Scope) == "test",
+		})
+	}
+	return deps, nil
+}
+
+// parseGradle reads the plugins and the dependencies given in group:artifact notation of a Gradle build
+func parseGradle(_, content string) ([]Dependency, error) {
+	var deps []Dependency
+	for _, match := range gradlePlugin.FindAllStringSubmatch(content, -1) {
+		deps = append(deps, Dependency{Name: match[1], Ecosystem: Gradle})
+	}
+	for _, match := range gradleDependency.FindAllStringSubmatch(content, -1) {
+		dev, ok := gradleConfigurations[match[1]]
+		if !ok {
+			continue
+		}
+		deps = append(deps, Dependency{Name: match[2] + ":" + match[3], Ecosystem: Maven, Dev: dev})
+	}
+	return deps, nil
+}
+
+// parseGemfile reads the gems of a Gemfile. Gems in development and test groups are development
+// dependencies.
+func parseGemfile(_, content string) ([]Dependency, error) {
+	var deps []Dependency
+	dev := false
+	for _, line := range lines(content) {
+		if match := gemGroup.FindStringSubmatch(line); match != nil {
+			dev = strings.Contains(match[1], ":development") || strings.Contains(match[1], ":test")
+			continue
+		}
+		if line == "end" {
+			dev = false
+			continue
+		}
+		if match := gemDeclaration.FindStringSubmatch(line); match != nil {
+			gemDev := dev || strings.Contains(line, "group: :development") || strings.Contains(line, "group: :test")
+			deps = append(deps, Dependency{Name: match[1], Ecosystem: RubyGems, Dev: gemDev})
+		}
+	}
+	return deps, nil
+}
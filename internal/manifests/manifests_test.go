@@ -0,0 +1,212 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/dto"
+)
+
+func TestIsManifest(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"go.mod", true},
+		{"web/package.json", true},
+		{"requirements.txt", true},
+		{"requirements-dev.txt", true},
+		{"requirements/base.txt", true},
+		{"pyproject.toml", true},
+		{"Cargo.toml", true},
+		{"pom.xml", true},
+		{"app/build.gradle.kts", true},
+		{"Gemfile", true},
+		{"composer.json", true},
+		{"package-lock.json", false},
+		{"Gemfile.lock", false},
+		{"docs/requirements.md", false},
+		{"notes.txt", false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsManifest(tc.path); got != tc.expected {
+			t.Errorf("IsManifest(%q) = %v, expected %v", tc.path, got, tc.expected)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		content  string
+		expected string
+	}{
+		{
+			"go.mod",
+			"go.mod",
+			"module example.com/app\n\ngo 1.22\n\nrequire github.com/spf13/cobra v1.8.0\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\t// a comment\n\tgolang.org/x/sys v0.20.0 // indirect\n)\n",
+			"go:github.com/spf13/cobra go:github.com/gin-gonic/gin",
+		},
+		{
+			"package.json",
+			"package.json",
+			`{"name": "app", "dependencies": {"react": "^18.0.0", "@aws-sdk/client-s3": "3"}, "devDependencies": {"jest": "^29"}}`,
+			"npm:@aws-sdk/client-s3 npm:react npm:jest(dev)",
+		},
+		{
+			"requirements",
+			"requirements-dev.txt",
+			"# tools\n-r requirements.txt\nDjango_REST>=3.0 # pinned\nuvicorn[standard]==0.29\ngit+https://example.com/pkg.git\nmypkg @ https://example.com/mypkg.whl\n",
+			"pypi:django-rest(dev) pypi:uvicorn(dev) pypi:mypkg(dev)",
+		},
+		{
+			"pyproject",
+			"pyproject.toml",
+			"[project]\nname = \"app\"\ndependencies = [\n  \"fastapi>=0.110\",\n  \"uvicorn[standard]\",\n]\n\n[project.optional-dependencies]\ntest = [\"pytest\"]\n\n[tool.poetry.dependencies]\npython = \"^3.11\"\nSQLAlchemy = \"^2.0\"\n\n[tool.poetry.group.dev.dependencies]\nblack = \"*\"\n",
+			"pypi:fastapi pypi:uvicorn pypi:pytest(dev) pypi:sqlalchemy pypi:black(dev)",
+		},
+		{
+			"Cargo.toml",
+			"Cargo.toml",
+			"[package]\nname = \"app\"\n\n[dependencies]\ntokio = { version = \"1\", features = [\"full\"] }\nserde = \"1\"\n\n[dependencies.axum]\nversion = \"0.7\"\nfeatures = [\n  \"macros\",\n]\n\n[dev-dependencies]\ncriterion = \"0.5\"\n",
+			"cargo:tokio cargo:serde cargo:axum cargo:criterion(dev)",
+		},
+		{
+			"pom.xml",
+			"pom.xml",
+			`<project><parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId></parent>
+<dependencies><dependency><groupId>org.postgresql</groupId><artifactId>postgresql</artifactId></dependency>
+<dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId><scope>test</scope></dependency></dependencies></project>`,
+			"maven:org.springframework.boot:spring-boot-starter-parent maven:org.postgresql:postgresql maven:org.junit.jupiter:junit-jupiter(dev)",
+		},
+		{
+			"pom.xml with a BOM",
+			"pom.xml",
+			`<project><dependencyManagement><dependencies>
+<dependency><groupId>org.springframework.boot</groupId><artifactId>spring-boot-dependencies</artifactId><type>pom</type><scope>import</scope></dependency>
+<dependency><groupId>org.hibernate.orm</groupId><artifactId>hibernate-core</artifactId></dependency>
+</dependencies></dependencyManagement>
+<dependencies><dependency><groupId>org.postgresql</groupId><artifactId>postgresql</artifactId></dependency></dependencies></project>`,
+			"maven:org.postgresql:postgresql",
+		},
+		{
+			"build.gradle.kts",
+			"build.gradle.kts",
+			"plugins {\n    id(\"com.android.application\")\n}\n\ndependencies {\n    implementation(platform(\"androidx.compose:compose-bom:2024.02.00\"))\n    implementation(\"io.ktor:ktor-client-core:2.3.0\")\n    testImplementation(\"junit:junit:4.13.2\")\n    implementation(project(\":core\"))\n}\n",
+			"gradle:com.android.application maven:androidx.compose:compose-bom maven:io.ktor:ktor-client-core maven:junit:junit(dev)",
+		},
+		{
+			"Gemfile",
+			"Gemfile",
+			"source \"https://rubygems.org\"\n\ngem \"rails\", \"~> 7.1\"\ngem 'pg'\n\ngroup :development, :test do\n  gem \"rspec-rails\"\nend\n\ngem \"sidekiq\"\n",
+			"rubygems:rails rubygems:pg rubygems:rspec-rails(dev) rubygems:sidekiq",
+		},
+		{
+			"composer.json",
+			"composer.json",
+			`{"require": {"php": ">=8.1", "ext-json": "*", "Laravel/Framework": "^10.0"}, "require-dev": {"phpunit/phpunit": "^10"}}`,
+			"composer:laravel/framework composer:phpunit/phpunit(dev)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps, err := Parse(tc.path, tc.content)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+			var names []string
+			for _, dep := range deps {
+				name := dep.Ecosystem + ":" + dep.Name
+				if dep.Dev {
+					name += "(dev)"
+				}
+				names = append(names, name)
+			}
+			if got := strings.Join(names, " "); got != tc.expected {
+				t.Errorf("Parse() = %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseRejectsMalformedManifests(t *testing.T) {
+	for _, filePath := range []string{"package.json", "composer.json", "pom.xml", "Makefile"} {
+		if _, err := Parse(filePath, "{not valid"); err == nil {
+			t.Errorf("Parse(%q) succeeded on malformed content", filePath)
+		}
+	}
+}
+
+func TestRecognize(t *testing.T) {
+	testCases := []struct {
+		dep      Dependency
+		expected string
+	}{
+		{Dependency{Name: "react", Ecosystem: NPM}, "[{React frontend}]"},
+		{Dependency{Name: "@aws-sdk/client-s3", Ecosystem: NPM}, "[{AWS SDK cloud}]"},
+		{Dependency{Name: "github.com/labstack/echo/v4", Ecosystem: Go}, "[{Echo web framework}]"},
+		{Dependency{Name: "androidx.compose.ui:ui", Ecosystem: Maven}, "[{Android mobile} {Jetpack Compose mobile}]"},
+		{Dependency{Name: "pg", Ecosystem: NPM}, "[{PostgreSQL database}]"},
+		{Dependency{Name: "react", Ecosystem: PyPI}, "[]"},
+		{Dependency{Name: "left-pad", Ecosystem: NPM}, "[]"},
+	}
+
+	for _, tc := range testCases {
+		if got := fmt.Sprint(Recognize(tc.dep)); got != tc.expected {
+			t.Errorf("Recognize(%s:%s) = %s, expected %s", tc.dep.Ecosystem, tc.dep.Name, got, tc.expected)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	inventory := Inventory(map[string]string{
+		"package.json":     `{"dependencies": {"react": "18", "left-pad": "1"}, "devDependencies": {"jest": "29", "react": "18"}}`,
+		"web/package.json": `{"devDependencies": {"vite": "5", "left-pad": "1"}}`,
+		"go.mod":           "module example.com/app\n\nrequire (\n\tgithub.com/spf13/cobra v1.8.0\n\tgithub.com/acme/util v0.1.0\n)\n",
+		"broken/pom.xml":   "<project>",
+		"empty/Gemfile":    "",
+	})
+	if inventory == nil {
+		t.Fatal("Inventory() returned nil")
+	}
+
+	got := fmt.Sprintf("%+v", *inventory)
+	expected := "{Manifests:[go.mod package.json web/package.json] Ecosystems:[go npm] Dependencies:6 DevDependencies:2 " +
+		"Technologies:[{Name:Cobra Category:cli} {Name:Jest Category:testing} {Name:React Category:frontend} {Name:Vite Category:build}] " +
+		"Libraries:[github.com/acme/util left-pad]}"
+	if got != expected {
+		t.Errorf("Inventory() = %s, expected %s", got, expected)
+	}
+
+	if inventory := Inventory(map[string]string{"pom.xml": "<project"}); inventory != nil {
+		t.Errorf("Inventory() of unreadable manifests = %+v, expected nil", *inventory)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	react := dto.Technology{Name: "React", Category: "frontend"}
+	jest := dto.Technology{Name: "Jest", Category: "testing"}
+	cobra := dto.Technology{Name: "Cobra", Category: "cli"}
+	repos := []*dto.Repository{
+		{Name: "alpha", Technologies: &dto.RepoTechnologies{Technologies: []dto.Technology{jest, react}}},
+		{Name: "beta"},
+		{Name: "gamma", Technologies: &dto.RepoTechnologies{Technologies: []dto.Technology{cobra, react}}},
+	}
+
+	var usages []string
+	for _, usage := range Combine(repos) {
+		usages = append(usages, fmt.Sprintf("%s:%s", usage.Name, strings.Join(usage.Repos, ",")))
+	}
+	expected := "React:alpha,gamma Cobra:gamma Jest:alpha"
+	if got := strings.Join(usages, " "); got != expected {
+		t.Errorf("Combine() = %s, expected %s", got, expected)
+	}
+
+	if usages := Combine(nil); usages != nil {
+		t.Errorf("Combine(nil) = %v, expected nil", usages)
+	}
+}
@@ -0,0 +1,215 @@
+package manifests
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"dev_profiler/internal/dto"
+)
+
+// Most unrecognized runtime dependencies listed per repository
+const maxLibraries = 30
+
+// technology is an entry of the embedded technologies.json. Packages are written as
+// ecosystem:name; a trailing * matches any name with that prefix, such as npm:@aws-sdk/*.
+type technology struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Packages []string `json:"packages"`
+}
+
+//go:embed technologies.json
+var embeddedCatalog []byte
+
+// catalog recognizes technologies from dependency names
+type catalog struct {
+	exact    map[string][]*technology
+	prefixes map[string][]*technology
+}
+
+var (
+	defaultCatalog *catalog
+	catalogOnce    sync.Once
+)
+
+// loadCatalog builds the catalog from the embedded table
+func loadCatalog() *catalog {
+	catalogOnce.Do(func() {
+		var table struct {
+			Technologies []technology `json:"technologies"`
+		}
+		if err := json.Unmarshal(embeddedCatalog, &table); err != nil {
+			panic(fmt.Sprintf("invalid embedded technology table: %v", err))
+		}
+
+		c := &catalog{exact: make(map[string][]*technology), prefixes: make(map[string][]*technology)}
+		for i := range table.Technologies {
+			tech := &table.Technologies[i]
+			for _, pkg := range tech.Packages {
+				if prefix, ok := strings.CutSuffix(pkg, "*"); ok {
+					c.prefixes[prefix] = append(c.prefixes[prefix], tech)
+				} else {
+					c.exact[pkg] = append(c.exact[pkg], tech)
+				}
+			}
+		}
+		defaultCatalog = c
+	})
+	return defaultCatalog
+}
+
+// recognize returns the technologies a dependency belongs to
+func (c *catalog) recognize(dep Dependency) []*technology {
+	key := dep.Ecosystem + ":" + dep.Name
+	techs := c.exact[key]
+	for prefix, prefixed := range c.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			techs = append(techs, prefixed...)
+		}
+	}
+	return techs
+}
+
+// Recognize returns the technologies a dependency belongs to, such as React for npm:react or
+// AWS SDK for npm:@aws-sdk/client-s3, sorted by name
+func Recognize(dep Dependency) []dto.Technology {
+	var techs []dto.Technology
+	seen := make(map[string]bool)
+	for _, tech := range loadCatalog().recognize(dep) {
+		if !seen[tech.Name] {
+			seen[tech.Name] = true
+			techs = append(techs, dto.Technology{Name: tech.Name, Category: tech.Category})
+		}
+	}
+	sort.Slice(techs, func(i, j int) bool {
+		return techs[i].Name < techs[j].Name
+	})
+	return techs
+}
+
+// Inventory builds the technology inventory of a repository from the contents of its manifests,
+// keyed by path. Manifests that are empty or can't be parsed are left out. It returns nil when
+// no manifest could be read.
+func Inventory(files map[string]string) *dto.RepoTechnologies {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	inventory := &dto.RepoTechnologies{}
+	dev := make(map[Dependency]bool) // Keyed without the Dev flag; false once any manifest needs it at runtime
+	ecosystems := make(map[string]bool)
+	for _, filePath := range paths {
+		if strings.TrimSpace(files[filePath]) == "" {
+			continue
+		}
+		deps, err := Parse(filePath, files[filePath])
+		if err != nil {
+			continue
+		}
+		inventory.Manifests = append(inventory.Manifests, filePath)
+		ecosystems[ecosystemOf(path.Base(filePath))] = true
+		for _, dep := range deps {
+			key := Dependency{Name: dep.Name, Ecosystem: dep.Ecosystem}
+			if isDev, seen := dev[key]; !seen || isDev {
+				dev[key] = dep.Dev
+			}
+		}
+	}
+	if len(inventory.Manifests) == 0 {
+		return nil
+	}
+
+	techs := make(map[dto.Technology]bool)
+	libraries := make(map[string]bool)
+	for dep, isDev := range dev {
+		recognized := Recognize(dep)
+		for _, tech := range recognized {
+			techs[tech] = true
+		}
+		// Gradle plugins name technologies but aren't dependencies of the code
+		if dep.Ecosystem == Gradle {
+			continue
+		}
+		inventory.Dependencies++
+		if isDev {
+			inventory.DevDependencies++
+		} else if len(recognized) == 0 {
+			libraries[dep.Name] = true
+		}
+	}
+
+	for ecosystem := range ecosystems {
+		inventory.Ecosystems = append(inventory.Ecosystems, ecosystem)
+	}
+	sort.Strings(inventory.Ecosystems)
+	for tech := range techs {
+		inventory.Technologies = append(inventory.Technologies, tech)
+	}
+	sort.Slice(inventory.Technologies, func(i, j int) bool {
+		return inventory.Technologies[i].Name < inventory.Technologies[j].Name
+	})
+	for library := range libraries {
+		inventory.Libraries = append(inventory.Libraries, library)
+	}
+	sort.Strings(inventory.Libraries)
+	inventory.Libraries = inventory.Libraries[:min(len(inventory.Libraries), maxLibraries)]
+	return inventory
+}
+
+// ecosystemOf returns the package registry a manifest declares dependencies for
+func ecosystemOf(base string) string {
+	switch base {
+	case "go.mod":
+		return Go
+	case "package.json":
+		return NPM
+	case "Cargo.toml":
+		return Cargo
+	case "pom.xml", "build.gradle", "build.gradle.kts":
+		return Maven
+	case "Gemfile":
+		return RubyGems
+	case "composer.json":
+		return Composer
+	}
+	return PyPI
+}
+
+// Combine lists the technologies of the given repositories with the repositories using each,
+// the most used first
+func Combine(repos []*dto.Repository) []*dto.TechnologyUsage {
+	byName := make(map[string]*dto.TechnologyUsage)
+	var usages []*dto.TechnologyUsage
+	for _, repo := range repos {
+		if repo.Technologies == nil {
+			continue
+		}
+		for _, tech := range repo.Technologies.Technologies {
+			usage, ok := byName[tech.Name]
+			if !ok {
+				usage = &dto.TechnologyUsage{Technology: tech}
+				byName[tech.Name] = usage
+				usages = append(usages, usage)
+			}
+			if !slices.Contains(usage.Repos, repo.Name) {
+				usage.Repos = append(usage.Repos, repo.Name)
+			}
+		}
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if len(usages[i].Repos) != len(usages[j].Repos) {
+			return len(usages[i].Repos) > len(usages[j].Repos)
+		}
+		return usages[i].Name < usages[j].Name
+	})
+	return usages
+}
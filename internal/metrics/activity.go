@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"dev_profiler/internal/dto"
+)
+
+// Activity computes commit statistics from a user's commits. Commits listed more than once,
+// such as those shared by a fork and its upstream, are counted once. It returns nil when there
+// are no commits.
+func Activity(commits []*dto.CommitDetail) *dto.CommitActivity {
+	seen := make(map[string]bool)
+	activity := &dto.CommitActivity{}
+	days := make(map[string]time.Time)
+	var sizes []int
+
+	for _, commit := range commits {
+		if seen[commit.SHA] {
+			continue
+		}
+		seen[commit.SHA] = true
+
+		activity.Commits++
+		activity.Additions += commit.Additions
+		activity.Deletions += commit.Deletions
+		sizes = append(sizes, commit.Additions+commit.Deletions)
+
+		if commit.Date.IsZero() {
+			continue
+		}
+		if activity.FirstCommit.IsZero() || commit.Date.Before(activity.FirstCommit) {
+			activity.FirstCommit = commit.Date
+		}
+		if commit.Date.After(activity.LastCommit) {
+			activity.LastCommit = commit.Date
+		}
+		// Hours and days are those of the author's clock, in the offset recorded with the commit
+		activity.HourHistogram[commit.Date.Hour()]++
+		activity.WeekdayHistogram[commit.Date.Weekday()]++
+		if activity.UTCOffsets == nil {
+			activity.UTCOffsets = make(map[string]int)
+		}
+		activity.UTCOffsets[commit.Date.Format("-07:00")]++
+
+		year, month, day := commit.Date.Date()
+		days[commit.Date.Format(time.DateOnly)] = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	if activity.Commits == 0 {
+		return nil
+	}
+
+	activity.Churn = activity.Additions + activity.Deletions
+	activity.MedianCommitSize = median(sizes)
+	activity.ActiveDays = len(days)
+	activity.LongestStreak = longestStreak(days)
+
+	// Count at least one week so a burst of commits on a single day isn't extrapolated
+	weeks := max(activity.LastCommit.Sub(activity.FirstCommit).Hours()/(24*7), 1)
+	activity.CommitsPerWeek = round(float64(activity.Commits) / weeks)
+	return activity
+}
+
+// longestStreak returns the most consecutive calendar days with commits
+func longestStreak(days map[string]time.Time) int {
+	dates := make([]time.Time, 0, len(days))
+	for _, date := range days {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	longest, streak := 0, 0
+	for i, date := range dates {
+		if i > 0 && date.Sub(dates[i-1]) == 24*time.Hour {
+			streak++
+		} else {
+			streak = 1
+		}
+		longest = max(longest, streak)
+	}
+	return longest
+}
+
+// median returns the middle value, or the lower of the two middle values for an even count
+func median(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	return sorted[(len(sorted)-1)/2]
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"dev_profiler/internal/dto"
+)
+
+func TestActivity(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		// 2024-03-04 is a Monday
+		return time.Date(2024, time.March, d, hour, 0, 0, 0, time.UTC)
+	}
+	commits := []*dto.CommitDetail{
+		{SHA: "a", Date: day(4, 9), Additions: 10, Deletions: 2},
+		{SHA: "b", Date: day(5, 9), Additions: 1},
+		{SHA: "c", Date: day(6, 22), Additions: 30, Deletions: 10},
+		{SHA: "d", Date: day(6, 23), Additions: 4},
+		{SHA: "e", Date: day(18, 9), Additions: 100, Deletions: 50},
+		{SHA: "c", Date: day(6, 22), Additions: 30, Deletions: 10}, // Listed again by a fork
+	}
+
+	activity := Activity(commits)
+	if activity == nil {
+		t.Fatal("Activity() returned nil")
+	}
+
+	if activity.Commits != 5 || activity.ActiveDays != 4 || activity.LongestStreak != 3 {
+		t.Errorf("Commits, active days and streak = %d, %d, %d, expected 5, 4, 3", activity.Commits, activity.ActiveDays, activity.LongestStreak)
+	}
+	if activity.Additions != 145 || activity.Deletions != 62 || activity.Churn != 207 || activity.MedianCommitSize != 12 {
+		t.Errorf("Unexpected line stats: %+v", activity)
+	}
+	if !activity.FirstCommit.Equal(day(4, 9)) || !activity.LastCommit.Equal(day(18, 9)) {
+		t.Errorf("Unexpected range %v to %v", activity.FirstCommit, activity.LastCommit)
+	}
+	if activity.CommitsPerWeek != 2.5 {
+		t.Errorf("CommitsPerWeek = %v, expected 2.5", activity.CommitsPerWeek)
+	}
+	if activity.HourHistogram[9] != 3 || activity.HourHistogram[22] != 1 || activity.HourHistogram[23] != 1 {
+		t.Errorf("Unexpected hour histogram: %v", activity.HourHistogram)
+	}
+	if activity.WeekdayHistogram[time.Monday] != 2 || activity.WeekdayHistogram[time.Wednesday] != 2 {
+		t.Errorf("Unexpected weekday histogram: %v", activity.WeekdayHistogram)
+	}
+}
+
+func TestActivityUsesCommitOffsets(t *testing.T) {
+	// 17:00 on a Monday at UTC-8 is 01:00 on Tuesday in UTC
+	pacific := time.FixedZone("UTC-8", -8*60*60)
+	activity := Activity([]*dto.CommitDetail{
+		{SHA: "a", Date: time.Date(2024, time.March, 4, 9, 0, 0, 0, pacific)},
+		{SHA: "b", Date: time.Date(2024, time.March, 4, 17, 0, 0, 0, pacific)},
+		{SHA: "c", Date: time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)},
+	})
+	if activity == nil {
+		t.Fatal("Activity() returned nil")
+	}
+	if activity.HourHistogram[9] != 1 || activity.HourHistogram[17] != 1 || activity.HourHistogram[1] != 0 {
+		t.Errorf("Expected commits at 9:00 and 17:00 author time, got %v", activity.HourHistogram)
+	}
+	if activity.WeekdayHistogram[time.Monday] != 2 || activity.WeekdayHistogram[time.Tuesday] != 1 {
+		t.Errorf("Unexpected weekday histogram: %v", activity.WeekdayHistogram)
+	}
+	if activity.ActiveDays != 2 {
+		t.Errorf("ActiveDays = %d, expected 2", activity.ActiveDays)
+	}
+	if len(activity.UTCOffsets) != 2 || activity.UTCOffsets["-08:00"] != 2 || activity.UTCOffsets["+00:00"] != 1 {
+		t.Errorf("UTCOffsets = %v, expected 2 at -08:00 and 1 at +00:00", activity.UTCOffsets)
+	}
+}
+
+func TestActivityWithoutCommits(t *testing.T) {
+	if activity := Activity(nil); activity != nil {
+		t.Errorf("Expected nil activity without commits, got %+v", activity)
+	}
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// goStructure finds the functions of a Go file with go/ast. The complexity of a function is one
+// plus its branches: if, for, range, non-default case clauses and && or || operators, including
+// those of function literals in its body. It reports false when the file doesn't parse.
+func goStructure(content string) (structure, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return structure{}, false
+	}
+
+	s := structure{method: MethodAST}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		s.functions = append(s.functions, function{
+			start:      fset.Position(fn.Pos()).Line - 1,
+			end:        fset.Position(fn.End()).Line - 1,
+			complexity: 1 + goBranches(fn.Body),
+		})
+	}
+	s.maxNesting = goNesting(file)
+	return s, true
+}
+
+// goBranches counts the decision points below a node
+func goBranches(node ast.Node) int {
+	branches := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			branches++
+		case *ast.CaseClause:
+			if n.List != nil {
+				branches++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				branches++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				branches++
+			}
+		}
+		return true
+	})
+	return branches
+}
+
+// goNesting returns the deepest nesting of control flow statements and function literals.
+// An else if chain counts as a single level.
+func goNesting(file *ast.File) int {
+	var stack []ast.Node
+	var nests []bool
+	depth, deepest := 0, 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			if nests[len(nests)-1] {
+				depth--
+			}
+			stack, nests = stack[:len(stack)-1], nests[:len(nests)-1]
+			return true
+		}
+
+		nested := false
+		switch n.(type) {
+		case *ast.IfStmt:
+			parent, isIf := ast.Node(nil), false
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+				_, isIf = parent.(*ast.IfStmt)
+			}
+			nested = !isIf || parent.(*ast.IfStmt).Else != n
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			nested = true
+		}
+		if nested {
+			depth++
+			deepest = max(deepest, depth)
+		}
+		stack, nests = append(stack, n), append(nests, nested)
+		return true
+	})
+	return deepest
+}
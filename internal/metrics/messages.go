@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+
+	"dev_profiler/internal/dto"
+)
+
+// Subject lengths: shorter subjects rarely say what changed, longer ones are cut off by git tools
+const (
+	minSubjectLength  = 10
+	maxSubjectLength  = 72
+	longSubjectLength = 100
+)
+
+// Points of each trait of a commit message; a message with all of them scores 100
+const (
+	subjectLengthPoints = 25
+	imperativePoints    = 20
+	bodyPoints          = 15
+	conventionalPoints  = 15
+	issueRefPoints      = 10
+	tidySubjectPoints   = 15
+
+	maxNoiseScore = 20 // Highest score of an uninformative message
+)
+
+var (
+	conventionalCommit = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([^)]+\))?!?: \S`)
+	issueReference     = regexp.MustCompile(`(^|[\s(])([\w.-]+/[\w.-]+)?#\d+\b|\bGH-\d+\b`)
+	trackerKey         = regexp.MustCompile(`\b([A-Z][A-Z0-9]+)-\d+\b`)
+	generatedMessage   = regexp.MustCompile(`^(Merge (pull request|branch|remote-tracking branch|tag) |Revert ")`)
+	bodyTrailer        = regexp.MustCompile(`(?i)^(signed-off-by|co-authored-by|reviewed-by|change-id):`)
+	noiseSubject       = regexp.MustCompile(`^((wip|fix|fixes|fixed|update|updates|updated|change|changes|changed|stuff|misc|tmp|temp|test|testing|typo|cleanup|more|commit|save|asdf|done|ok)` +
+		`|(small|minor|quick|some|more) (fix|change|update|tweak)(e?s)?` +
+		`|(fix|update|change)(e?[sd])? (bug|bugs|stuff|things|code|file|files)` +
+		`|(update|create|delete|add) [\w./-]+\.\w+` +
+		`|add files via upload|\.+|-+)[.!]*$`)
+)
+
+// Verbs whose base form starts an imperative subject such as "Add retry to the client"
+var imperativeVerbs = map[string]bool{
+	"add": true, "adjust": true, "align": true, "allow": true, "apply": true, "avoid": true, "bump": true,
+	"build": true, "cache": true, "change": true, "check": true, "clean": true, "configure": true,
+	"convert": true, "correct": true, "create": true, "delete": true, "deprecate": true, "disable": true,
+	"document": true, "drop": true, "enable": true, "ensure": true, "expose": true, "extract": true,
+	"fix": true, "format": true, "generate": true, "handle": true, "hide": true, "ignore": true,
+	"implement": true, "improve": true, "include": true, "initialize": true, "install": true,
+	"introduce": true, "keep": true, "limit": true, "load": true, "log": true, "make": true, "merge": true,
+	"migrate": true, "move": true, "optimize": true, "parse": true, "pin": true, "polish": true,
+	"port": true, "prepare": true, "prevent": true, "publish": true, "raise": true, "read": true,
+	"reduce": true, "refactor": true, "release": true, "remove": true, "rename": true, "render": true,
+	"reorganize": true, "replace": true, "reset": true, "resolve": true, "restore": true, "return": true,
+	"revert": true, "rewrite": true, "run": true, "send": true, "set": true, "show": true,
+	"simplify": true, "skip": true, "sort": true, "speed": true, "split": true, "start": true,
+	"stop": true, "store": true, "support": true, "switch": true, "test": true, "track": true,
+	"tweak": true, "update": true, "upgrade": true, "use": true, "validate": true, "wrap": true,
+	"write": true,
+}
+
+// Prefixes of words that look like issue tracker keys such as PROJ-123 but aren't
+var notTrackerKeys = map[string]bool{"UTF": true, "ISO": true, "SHA": true, "AES": true, "HTTP": true}
+
+// messageTraits are the traits of a single commit message
+type messageTraits struct {
+	subjectLength int
+	imperative    bool
+	conventional  bool
+	body          bool
+	issueRef      bool
+	tidy          bool
+	noise         bool
+}
+
+// analyzeMessage finds the traits of a commit message
+func analyzeMessage(message string) messageTraits {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), "\n")
+	subject := strings.TrimSpace(lines[0])
+	traits := messageTraits{
+		subjectLength: len([]rune(subject)),
+		conventional:  conventionalCommit.MatchString(subject),
+		issueRef:      hasIssueReference(message),
+		tidy:          !strings.HasSuffix(subject, "."),
+	}
+
+	// Judge the description of a conventional commit, after its type and scope
+	description := subject
+	if traits.conventional {
+		_, description, _ = strings.Cut(subject, ": ")
+	}
+	firstWord, _, _ := strings.Cut(strings.ToLower(description), " ")
+	traits.imperative = imperativeVerbs[strings.TrimRight(firstWord, ":,")]
+	traits.noise = noiseSubject.MatchString(strings.ToLower(strings.TrimSpace(description)))
+
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line != "" && !bodyTrailer.MatchString(line) {
+			traits.body = true
+			break
+		}
+	}
+	return traits
+}
+
+// hasIssueReference reports whether a message refers to an issue or pull request, such as #12,
+// owner/repo#12 or an issue tracker key like PROJ-123
+func hasIssueReference(message string) bool {
+	if issueReference.MatchString(message) {
+		return true
+	}
+	for _, match := range trackerKey.FindAllStringSubmatch(message, -1) {
+		if !notTrackerKeys[match[1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// score rates a message from 0 to 100 on its traits. Uninformative messages such as "wip" or
+// "update" score low however they are written.
+func (t messageTraits) score() int {
+	score := 0
+	switch {
+	case t.subjectLength >= minSubjectLength && t.subjectLength <= maxSubjectLength:
+		score += subjectLengthPoints
+	case t.subjectLength > maxSubjectLength && t.subjectLength <= longSubjectLength:
+		score += subjectLengthPoints / 2
+	}
+	if t.imperative {
+		score += imperativePoints
+	}
+	if t.body {
+		score += bodyPoints
+	}
+	if t.conventional {
+		score += conventionalPoints
+	}
+	if t.issueRef {
+		score += issueRefPoints
+	}
+	if t.tidy {
+		score += tidySubjectPoints
+	}
+	if t.noise {
+		score = min(score, maxNoiseScore)
+	}
+	return score
+}
+
+// Messages summarizes the quality of the commit messages of the given commits. Merge and revert
+// commits with generated messages are counted but not scored, and commits listed more than once
+// are counted once. It returns nil when there are no commits.
+func Messages(commits []*dto.CommitDetail) *dto.MessageQuality {
+	seen := make(map[string]bool)
+	quality := &dto.MessageQuality{}
+	var totalScore, totalLength, imperative, conventional, body, issueRefs, noise, long int
+
+	for _, commit := range commits {
+		if seen[commit.SHA] {
+			continue
+		}
+		seen[commit.SHA] = true
+
+		if generatedMessage.MatchString(commit.Message) {
+			quality.Generated++
+			continue
+		}
+
+		traits := analyzeMessage(commit.Message)
+		score := traits.score()
+		quality.Messages++
+		totalScore += score
+		quality.ScoreHistogram[min(score/20, len(quality.ScoreHistogram)-1)]++
+		totalLength += traits.subjectLength
+		if traits.subjectLength > maxSubjectLength {
+			long++
+		}
+		if traits.imperative {
+			imperative++
+		}
+		if traits.conventional {
+			conventional++
+		}
+		if traits.body {
+			body++
+		}
+		if traits.issueRef {
+			issueRefs++
+		}
+		if traits.noise {
+			noise++
+		}
+	}
+	if quality.Messages == 0 && quality.Generated == 0 {
+		return nil
+	}
+	if quality.Messages == 0 {
+		return quality
+	}
+
+	share := func(count int) float64 {
+		return round(float64(count) / float64(quality.Messages))
+	}
+	quality.AvgScore = round(float64(totalScore) / float64(quality.Messages))
+	quality.AvgSubjectLength = round(float64(totalLength) / float64(quality.Messages))
+	quality.LongSubjectRatio = share(long)
+	quality.ImperativeRatio = share(imperative)
+	quality.ConventionalRatio = share(conventional)
+	quality.BodyRatio = share(body)
+	quality.IssueRefRatio = share(issueRefs)
+	quality.NoiseRatio = share(noise)
+	return quality
+}
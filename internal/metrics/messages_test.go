@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"testing"
+
+	"dev_profiler/internal/dto"
+)
+
+func TestMessageScore(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected int
+	}{
+		{"complete conventional commit", "fix(api): handle empty pages in the commit listing\n\nThe API returns 204 for empty repositories.\n\nFixes #42", 100},
+		{"imperative with body", "Add retry to the GitHub client\n\nSecondary rate limits are retried after the advertised delay.", 75},
+		{"imperative subject only", "Add retry to the GitHub client", 60},
+		{"past tense with period", "Added retry to the client.", 25},
+		{"trailers are not a body", "Add retry to the GitHub client\n\nSigned-off-by: Octo Cat <octo@example.com>", 60},
+		{"tracker key", "PROJ-123 Support SSO logins", 50},
+		{"encoding name is not a tracker key", "Read files as UTF-8 always", 60},
+		{"long subject", "Add retry to the GitHub client so that secondary rate limits and abuse detection are handled", 47},
+		{"noise", "wip", 15},
+		{"noise in a conventional commit", "fix: typo", 20},
+		{"web editor default", "Update README.md", 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := analyzeMessage(tc.message).score(); got != tc.expected {
+				t.Errorf("score(%q) = %d, expected %d (%+v)", tc.message, got, tc.expected, analyzeMessage(tc.message))
+			}
+		})
+	}
+}
+
+func TestMessages(t *testing.T) {
+	commits := []*dto.CommitDetail{
+		{SHA: "a", Message: "feat: add commit statistics\n\nCloses #7"},
+		{SHA: "b", Message: "Fix nil pointer in the tree walker"},
+		{SHA: "c", Message: "wip"},
+		{SHA: "d", Message: "Updated stuff."},
+		{SHA: "e", Message: "Merge pull request #9 from octocat/feature"},
+		{SHA: "b", Message: "Fix nil pointer in the tree walker"}, // Listed again by a fork
+	}
+
+	quality := Messages(commits)
+	if quality == nil {
+		t.Fatal("Messages() returned nil")
+	}
+
+	if quality.Messages != 4 || quality.Generated != 1 {
+		t.Errorf("Expected 4 scored and 1 generated message, got %d and %d", quality.Messages, quality.Generated)
+	}
+	if quality.ScoreHistogram != [5]int{1, 1, 0, 1, 1} {
+		t.Errorf("Unexpected score histogram: %v", quality.ScoreHistogram)
+	}
+	if quality.ImperativeRatio != 0.5 || quality.ConventionalRatio != 0.25 || quality.BodyRatio != 0.25 ||
+		quality.IssueRefRatio != 0.25 || quality.NoiseRatio != 0.5 {
+		t.Errorf("Unexpected ratios: %+v", quality)
+	}
+}
+
+func TestMessagesWithoutCommits(t *testing.T) {
+	if quality := Messages(nil); quality != nil {
+		t.Errorf("Expected nil quality without commits, got %+v", quality)
+	}
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+
+	"dev_profiler/internal/dto"
+)
+
+// Methods recorded in dto.FileMetrics.Method
+const (
+	MethodAST    = "ast"    // Functions and complexity from a parsed syntax tree
+	MethodTokens = "tokens" // Functions and complexity approximated from tokens and indentation
+)
+
+// Runs of at least this many code lines repeated within a file count as duplication
+const duplicateWindow = 4
+
+// Normalized code lines shorter than this, such as closing braces, are ignored for duplication
+const minDuplicateLineLength = 8
+
+// function is a function found in a file, with lines numbered from 0
+type function struct {
+	start, end int
+	complexity int
+}
+
+// structure holds the functions and nesting depth of a file
+type structure struct {
+	functions  []function
+	maxNesting int
+	method     string
+}
+
+// Analyze computes static metrics for a file in the given language. Go files are parsed;
+// other languages, and Go files that don't parse, use the token-based approximation.
+func Analyze(language, content string) *dto.FileMetrics {
+	style := styleFor(language)
+	lines := splitCode(content, style)
+
+	m := &dto.FileMetrics{}
+	for _, line := range lines {
+		switch {
+		case line.code != "":
+			m.CodeLines++
+		case line.comment:
+			m.CommentLines++
+		default:
+			m.BlankLines++
+		}
+	}
+	if total := m.CodeLines + m.CommentLines; total > 0 {
+		m.CommentRatio = round(float64(m.CommentLines) / float64(total))
+	}
+
+	m.DuplicateLines = duplicateLines(lines)
+	if m.CodeLines > 0 {
+		m.DuplicationRatio = round(float64(m.DuplicateLines) / float64(m.CodeLines))
+	}
+
+	var s structure
+	var parsed bool
+	if language == "Go" {
+		s, parsed = goStructure(content)
+	}
+	if !parsed {
+		s = tokenStructure(lines, style)
+	}
+
+	m.Method = s.method
+	m.MaxNesting = s.maxNesting
+	m.Functions = len(s.functions)
+	totalLength := 0
+	for _, fn := range s.functions {
+		length := fn.end - fn.start + 1
+		totalLength += length
+		m.MaxFunctionLength = max(m.MaxFunctionLength, length)
+		m.Complexity += fn.complexity
+		m.MaxComplexity = max(m.MaxComplexity, fn.complexity)
+	}
+	if m.Functions > 0 {
+		m.AvgFunctionLength = round(float64(totalLength) / float64(m.Functions))
+	}
+	return m
+}
+
+// Summarize combines the metrics of a repository's files, weighting ratios by code lines and
+// averages by function count. It returns nil when no file has metrics.
+func Summarize(files []*dto.FileMetrics) *dto.RepoMetrics {
+	summary := &dto.RepoMetrics{}
+	commentLines, duplicateLines, functionLines, complexity := 0, 0, 0.0, 0
+	for _, m := range files {
+		if m == nil {
+			continue
+		}
+		summary.Files++
+		summary.CodeLines += m.CodeLines
+		summary.Functions += m.Functions
+		commentLines += m.CommentLines
+		duplicateLines += m.DuplicateLines
+		functionLines += m.AvgFunctionLength * float64(m.Functions)
+		complexity += m.Complexity
+		summary.MaxFunctionLength = max(summary.MaxFunctionLength, m.MaxFunctionLength)
+		summary.MaxComplexity = max(summary.MaxComplexity, m.MaxComplexity)
+		summary.MaxNesting = max(summary.MaxNesting, m.MaxNesting)
+	}
+	if summary.Files == 0 {
+		return nil
+	}
+
+	if total := summary.CodeLines + commentLines; total > 0 {
+		summary.CommentRatio = round(float64(commentLines) / float64(total))
+	}
+	if summary.CodeLines > 0 {
+		summary.DuplicationRatio = round(float64(duplicateLines) / float64(summary.CodeLines))
+	}
+	if summary.Functions > 0 {
+		summary.AvgFunctionLength = round(functionLines / float64(summary.Functions))
+		summary.AvgComplexity = round(float64(complexity) / float64(summary.Functions))
+	}
+	return summary
+}
+
+// duplicateLines counts code lines that belong to a run of duplicateWindow lines already seen
+// earlier in the file. Trivial lines are skipped so repeated closing braces don't count.
+func duplicateLines(lines []codeLine) int {
+	var normalized []string
+	for _, line := range lines {
+		text := strings.Join(strings.Fields(line.code), " ")
+		if len(text) >= minDuplicateLineLength {
+			normalized = append(normalized, text)
+		}
+	}
+
+	seen := make(map[string]int)
+	duplicate := make([]bool, len(normalized))
+	for i := 0; i+duplicateWindow <= len(normalized); i++ {
+		key := strings.Join(normalized[i:i+duplicateWindow], "\n")
+		first, exists := seen[key]
+		if !exists {
+			seen[key] = i
+			continue
+		}
+		// Overlapping runs, such as a repeated line, aren't duplicated blocks
+		if i-first < duplicateWindow {
+			continue
+		}
+		for j := i; j < i+duplicateWindow; j++ {
+			duplicate[j] = true
+		}
+	}
+
+	count := 0
+	for _, dup := range duplicate {
+		if dup {
+			count++
+		}
+	}
+	return count
+}
+
+// round rounds a ratio or average to two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
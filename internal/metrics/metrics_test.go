@@ -0,0 +1,262 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/dto"
+)
+
+const goSource = `package sample
+
+// Sum adds the positive values
+func Sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 && v < 100 {
+			total += v
+		}
+	}
+	return total
+}
+
+/* Kind names a value
+   in a block comment */
+func Kind(v int) string {
+	switch {
+	case v < 0:
+		return "negative"
+	case v == 0:
+		return "zero"
+	default:
+		return "positive"
+	}
+}
+`
+
+func TestAnalyzeGo(t *testing.T) {
+	m := Analyze("Go", goSource)
+
+	if m.Method != MethodAST {
+		t.Errorf("Expected Go to be parsed, got method %q", m.Method)
+	}
+	if m.Functions != 2 || m.MaxFunctionLength != 10 || m.AvgFunctionLength != 9.5 {
+		t.Errorf("Unexpected functions: %d, max length %d, average %.2f", m.Functions, m.MaxFunctionLength, m.AvgFunctionLength)
+	}
+	// Sum: 1 + range + if + && = 4, Kind: 1 + two cases = 3
+	if m.Complexity != 7 || m.MaxComplexity != 4 {
+		t.Errorf("Expected complexity 7 with a maximum of 4, got %d and %d", m.Complexity, m.MaxComplexity)
+	}
+	if m.MaxNesting != 2 {
+		t.Errorf("Expected nesting 2, got %d", m.MaxNesting)
+	}
+	if m.CommentLines != 3 || m.BlankLines != 3 || m.CodeLines != 20 {
+		t.Errorf("Unexpected line counts: %d code, %d comment, %d blank", m.CodeLines, m.CommentLines, m.BlankLines)
+	}
+	if m.CommentRatio != 0.13 {
+		t.Errorf("Expected comment ratio 0.13, got %.2f", m.CommentRatio)
+	}
+}
+
+func TestAnalyzeGoFallsBackToTokens(t *testing.T) {
+	// Snippets that don't parse still get approximate metrics
+	m := Analyze("Go", "func broken( {\n\tif x {\n\t}\n}\n")
+	if m.Method != MethodTokens {
+		t.Errorf("Expected the token approximation, got %q", m.Method)
+	}
+}
+
+func TestAnalyzeTokens(t *testing.T) {
+	testCases := []struct {
+		name       string
+		language   string
+		content    string
+		functions  int
+		complexity int
+		nesting    int
+		comments   int
+	}{
+		{
+			name:     "python",
+			language: "Python",
+			content: `"""Module docstring."""
+
+def classify(x):
+    # Branches on the value
+    if x > 0 and x < 10:
+        return "small"
+    elif x >= 10:
+        for i in range(x):
+            if i % 2:
+                print(i)
+    return "other"
+
+
+def identity(x):
+    return x
+`,
+			functions:  2,
+			complexity: 7, // classify: 1 + if + and + elif + for + if = 6, identity: 1
+			nesting:    3,
+			comments:   2,
+		},
+		{
+			name:     "javascript",
+			language: "JavaScript",
+			content: `// Handlers for the page
+export function load(items) {
+  const ok = items.filter((item) => {
+    return item.ok || item.force;
+  });
+  if (ok.length > 0) {
+    for (const item of ok) {
+      render(item);
+    }
+  }
+}
+
+class View {
+  render(item) {
+    return "if (" + item + ")";
+  }
+}
+`,
+			functions:  3,
+			complexity: 6, // load: 1 + if + for = 3, arrow: 1 + || = 2, render: 1
+			nesting:    2,
+			comments:   1,
+		},
+		{
+			name:     "c prototypes",
+			language: "C",
+			content: `#include <stdio.h>
+
+int add(int a, int b);
+
+int add(int a,
+        int b)
+{
+    while (a > 0) {
+        a--;
+        b++;
+    }
+    return b;
+}
+`,
+			functions:  1,
+			complexity: 2,
+			nesting:    1,
+		},
+		{
+			name:     "rust lifetimes",
+			language: "Rust",
+			content: `pub fn longest<'a>(x: &'a str, y: &'a str) -> &'a str {
+    if x.len() > y.len() { x } else { y }
+}
+
+fn quote() -> char {
+    '{'
+}
+`,
+			functions:  2,
+			complexity: 3,
+			nesting:    1,
+		},
+		{
+			name:     "ruby",
+			language: "Ruby",
+			content: `class Greeter
+  def greet(name)
+    if name.nil? or name.empty?
+      "Hello"
+    else
+      "Hello #{name}"
+    end
+  end
+
+  def bye
+    "Bye"
+  end
+end
+`,
+			functions:  2,
+			complexity: 4,
+			nesting:    1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := Analyze(tc.language, tc.content)
+			if m.Method != MethodTokens {
+				t.Errorf("Expected the token approximation, got %q", m.Method)
+			}
+			if m.Functions != tc.functions {
+				t.Errorf("Expected %d functions, got %d", tc.functions, m.Functions)
+			}
+			if m.Complexity != tc.complexity {
+				t.Errorf("Expected complexity %d, got %d", tc.complexity, m.Complexity)
+			}
+			if m.MaxNesting != tc.nesting {
+				t.Errorf("Expected nesting %d, got %d", tc.nesting, m.MaxNesting)
+			}
+			if m.CommentLines != tc.comments {
+				t.Errorf("Expected %d comment lines, got %d", tc.comments, m.CommentLines)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDuplication(t *testing.T) {
+	block := strings.Join([]string{
+		"    value := compute(input)",
+		"    if value > threshold {",
+		"        report(value, input)",
+		"    }",
+		"    results = append(results, value)",
+	}, "\n")
+	content := "func a() {\n" + block + "\n}\n\nfunc b() {\n" + block + "\n}\n"
+
+	m := Analyze("Go", content)
+	// The repeated block has four non-trivial lines, the closing brace is ignored
+	if m.DuplicateLines != 4 {
+		t.Errorf("Expected 4 duplicate lines, got %d", m.DuplicateLines)
+	}
+	if m.DuplicationRatio != 0.29 {
+		t.Errorf("Expected duplication ratio 0.29, got %.2f", m.DuplicationRatio)
+	}
+
+	// A single line repeated a few times isn't a duplicated block
+	repeated := strings.Repeat("    results = append(results, value)\n", 6)
+	if got := Analyze("Go", repeated).DuplicateLines; got != 0 {
+		t.Errorf("Expected no duplicate lines for a repeated line, got %d", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if Summarize(nil) != nil || Summarize([]*dto.FileMetrics{nil}) != nil {
+		t.Error("Summarize() should return nil without metrics")
+	}
+
+	summary := Summarize([]*dto.FileMetrics{
+		{CodeLines: 80, CommentLines: 20, Functions: 4, AvgFunctionLength: 10, MaxFunctionLength: 20, Complexity: 8, MaxComplexity: 5, MaxNesting: 2, DuplicateLines: 8},
+		nil,
+		{CodeLines: 20, CommentLines: 0, Functions: 1, AvgFunctionLength: 15, MaxFunctionLength: 15, Complexity: 2, MaxComplexity: 2, MaxNesting: 4},
+	})
+
+	expected := dto.RepoMetrics{
+		Files:             2,
+		CodeLines:         100,
+		CommentRatio:      0.17,
+		Functions:         5,
+		AvgFunctionLength: 11,
+		MaxFunctionLength: 20,
+		AvgComplexity:     2,
+		MaxComplexity:     5,
+		MaxNesting:        4,
+		DuplicationRatio:  0.08,
+	}
+	if *summary != expected {
+		t.Errorf("Summarize() = %+v, want %+v", *summary, expected)
+	}
+}
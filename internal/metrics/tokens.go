@@ -0,0 +1,382 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commentStyle describes comment and block syntax of a language family
+type commentStyle struct {
+	line       []string // Line comment prefixes
+	blockStart string   // Block comment delimiters, empty when the language has none
+	blockEnd   string
+	indented   bool // Blocks are delimited by indentation or "end" instead of braces
+	wordLogic  bool // "and" and "or" are logical operators
+	chars      bool // Single quotes delimit character literals, so a lone quote is a lifetime or label
+}
+
+var (
+	cStyle      = commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	charStyle   = commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/", chars: true}
+	phpStyle    = commentStyle{line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle   = commentStyle{line: []string{"#"}}
+	perlStyle   = commentStyle{line: []string{"#"}, wordLogic: true}
+	pythonStyle = commentStyle{line: []string{"#"}, blockStart: `"""`, blockEnd: `"""`, indented: true, wordLogic: true}
+	rubyStyle   = commentStyle{line: []string{"#"}, blockStart: "=begin", blockEnd: "=end", indented: true, wordLogic: true}
+	hashIndent  = commentStyle{line: []string{"#"}, indented: true, wordLogic: true}
+	luaStyle    = commentStyle{line: []string{"--"}, blockStart: "--[[", blockEnd: "]]", indented: true, wordLogic: true}
+	sqlStyle    = commentStyle{line: []string{"--"}, blockStart: "/*", blockEnd: "*/"}
+	haskell     = commentStyle{line: []string{"--"}, blockStart: "{-", blockEnd: "-}", indented: true}
+	lispStyle   = commentStyle{line: []string{";"}}
+	erlangStyle = commentStyle{line: []string{"%"}}
+	matlabStyle = commentStyle{line: []string{"%"}, blockStart: "%{", blockEnd: "%}", indented: true}
+	shellStyle  = commentStyle{line: []string{"#"}}
+	hclStyle    = commentStyle{line: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/"}
+	powershell  = commentStyle{line: []string{"#"}, blockStart: "<#", blockEnd: "#>"}
+)
+
+// Comment styles by language name, languages not listed use C style comments and braces
+var languageStyles = map[string]commentStyle{
+	"C":           charStyle,
+	"C++":         charStyle,
+	"C#":          charStyle,
+	"CUDA":        charStyle,
+	"Go":          charStyle,
+	"Java":        charStyle,
+	"Kotlin":      charStyle,
+	"Objective-C": charStyle,
+	"Rust":        charStyle,
+	"Scala":       charStyle,
+	"Zig":         charStyle,
+	"PHP":         phpStyle,
+	"Python":      pythonStyle,
+	"Starlark":    pythonStyle,
+	"Ruby":        rubyStyle,
+	"Crystal":     hashIndent,
+	"Elixir":      hashIndent,
+	"Julia":       hashIndent,
+	"Nim":         hashIndent,
+	"GDScript":    hashIndent,
+	"Perl":        perlStyle,
+	"R":           hashStyle,
+	"Shell":       shellStyle,
+	"Fish":        shellStyle,
+	"Makefile":    hashStyle,
+	"Dockerfile":  hashStyle,
+	"CMake":       hashStyle,
+	"Nix":         hashStyle,
+	"HCL":         hclStyle,
+	"PowerShell":  powershell,
+	"Lua":         luaStyle,
+	"SQL":         sqlStyle,
+	"PLpgSQL":     sqlStyle,
+	"Haskell":     haskell,
+	"Elm":         haskell,
+	"Clojure":     lispStyle,
+	"Erlang":      erlangStyle,
+	"Prolog":      erlangStyle,
+	"MATLAB":      matlabStyle,
+}
+
+// styleFor returns the comment style of a language
+func styleFor(language string) commentStyle {
+	if style, ok := languageStyles[language]; ok {
+		return style
+	}
+	return cStyle
+}
+
+// codeLine is a source line with comments removed and string contents blanked
+type codeLine struct {
+	raw     string
+	code    string // Code on the line without comments, string literals replaced by ""
+	comment bool   // The line holds a comment
+}
+
+// splitCode splits content into lines, separating code from comments and blanking string
+// literals so keywords inside them aren't counted. Quoted strings end at the line end; block
+// comments and backquoted strings may span lines.
+func splitCode(content string, style commentStyle) []codeLine {
+	rawLines := strings.Split(content, "\n")
+	lines := make([]codeLine, len(rawLines))
+	inBlock, inBacktick := false, false
+
+	for i, raw := range rawLines {
+		line := codeLine{raw: raw}
+		var code strings.Builder
+		for pos := 0; pos < len(raw); {
+			rest := raw[pos:]
+			switch {
+			case inBlock:
+				line.comment = true
+				end := strings.Index(rest, style.blockEnd)
+				if end < 0 {
+					pos = len(raw)
+					continue
+				}
+				inBlock = false
+				pos += end + len(style.blockEnd)
+			case inBacktick:
+				end := strings.IndexByte(rest, '`')
+				if end < 0 {
+					pos = len(raw)
+					continue
+				}
+				inBacktick = false
+				code.WriteString("``")
+				pos += end + 1
+			case style.blockStart != "" && strings.HasPrefix(rest, style.blockStart):
+				line.comment = true
+				inBlock = true
+				pos += len(style.blockStart)
+			case hasAnyPrefix(rest, style.line):
+				line.comment = true
+				pos = len(raw)
+			case rest[0] == '`':
+				inBacktick = true
+				pos++
+			case rest[0] == '"' || rest[0] == '\'':
+				end := closingQuote(rest)
+				if style.chars && rest[0] == '\'' && end > 4 && rest[1] != '\\' {
+					// Not a character literal
+					code.WriteByte(rest[0])
+					pos++
+					continue
+				}
+				code.WriteString(`""`)
+				pos += end
+			default:
+				code.WriteByte(rest[0])
+				pos++
+			}
+		}
+		line.code = strings.TrimSpace(code.String())
+		if inBacktick && line.code == "" {
+			line.code = "``"
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// closingQuote returns the length of the quoted string starting s, ending at the line end when
+// the quote isn't closed
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// hasAnyPrefix reports whether s starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// Words and operators adding a branch to the cyclomatic complexity
+	decisionPattern = regexp.MustCompile(`\b(if|elif|elsif|for|foreach|while|until|unless|case|when|catch|except|rescue|guard)\b|&&|\|\|`)
+	wordLogicRegexp = regexp.MustCompile(`\b(and|or)\b`)
+
+	// Lines starting a control flow block
+	controlPattern = regexp.MustCompile(`^(\}\s*)?(if|else|elif|elsif|for|foreach|while|until|unless|do|switch|case|when|match|select|try|catch|except|rescue|finally|with|loop|guard)\b`)
+
+	// Function headers using a keyword
+	keywordFunction = regexp.MustCompile(`^(export\s+|pub(\([\w:]+\))?\s+|async\s+|local\s+|static\s+|private\s+|public\s+|protected\s+|override\s+|inline\s+|suspend\s+)*(func|function|fn|fun|def|defp|defmacro|sub|proc|method)\b`)
+	// Function headers of C-like languages: a return type or modifiers, a name and parameters
+	signatureFunction = regexp.MustCompile(`^[\w$<>\[\],.*&:?@~ ]*?[\w$~]+\s*\([^;]*\)?[^;=]*$`)
+	// Anonymous functions and arrow functions with a block body
+	anonymousFunction = regexp.MustCompile(`\bfunction\s*\*?\s*[\w$]*\s*\(|=>\s*\{$`)
+)
+
+// Words that start a statement rather than a function signature
+var statementWords = map[string]bool{
+	"if": true, "else": true, "for": true, "foreach": true, "while": true, "switch": true, "catch": true,
+	"return": true, "new": true, "do": true, "try": true, "using": true, "lock": true, "synchronized": true,
+	"sizeof": true, "throw": true, "await": true, "yield": true, "case": true, "when": true, "match": true,
+	"elif": true, "unless": true, "until": true, "with": true, "assert": true, "delete": true, "typeof": true,
+}
+
+// isFunctionHeader reports whether a code line starts a function
+func isFunctionHeader(code string, style commentStyle) bool {
+	if keywordFunction.MatchString(code) || anonymousFunction.MatchString(code) {
+		return true
+	}
+	if style.indented || !signatureFunction.MatchString(code) {
+		return false
+	}
+	first := strings.FieldsFunc(code, func(r rune) bool {
+		return r == ' ' || r == '(' || r == '\t'
+	})
+	return len(first) > 0 && !statementWords[first[0]] && strings.Contains(code, "(")
+}
+
+// decisions counts the branches on a code line
+func decisions(code string, style commentStyle) int {
+	count := len(decisionPattern.FindAllStringIndex(code, -1))
+	if style.wordLogic {
+		count += len(wordLogicRegexp.FindAllStringIndex(code, -1))
+	}
+	return count
+}
+
+// tokenStructure approximates functions and nesting from code lines, following braces or, for
+// indented languages, indentation
+func tokenStructure(lines []codeLine, style commentStyle) structure {
+	if style.indented {
+		return indentStructure(lines, style)
+	}
+	return braceStructure(lines, style)
+}
+
+// openFunction is a function whose end hasn't been found yet
+type openFunction struct {
+	function
+	depth    int  // Brace depth outside the function, or indentation of the header
+	started  bool // The function body has been opened
+	parens   int  // Unclosed parentheses of the header
+	headerAt int  // Line where the header's parameters were closed, -1 before
+}
+
+// braceStructure finds functions and nesting in languages whose blocks use braces
+func braceStructure(lines []codeLine, style commentStyle) structure {
+	s := structure{method: MethodTokens}
+	var open []*openFunction
+	var braces []bool // Open braces, true when they start a control flow block
+	controls := 0
+
+	for i, line := range lines {
+		if line.code == "" {
+			continue
+		}
+		if isFunctionHeader(line.code, style) {
+			open = append(open, &openFunction{function: function{start: i, complexity: 1}, depth: len(braces), headerAt: -1})
+		}
+		if n := len(open); n > 0 {
+			open[n-1].complexity += decisions(line.code, style)
+		}
+
+		control := controlPattern.MatchString(line.code)
+		for _, c := range line.code {
+			switch c {
+			case '{':
+				braces = append(braces, control)
+				if control {
+					controls++
+					s.maxNesting = max(s.maxNesting, controls)
+				}
+				if n := len(open); n > 0 && !open[n-1].started && len(braces) == open[n-1].depth+1 {
+					open[n-1].started = true
+				}
+			case '}':
+				if len(braces) == 0 {
+					continue
+				}
+				if braces[len(braces)-1] {
+					controls--
+				}
+				braces = braces[:len(braces)-1]
+				if n := len(open); n > 0 && open[n-1].started && len(braces) == open[n-1].depth {
+					fn := open[n-1].function
+					fn.end = i
+					s.functions = append(s.functions, fn)
+					open = open[:n-1]
+				}
+			case ';':
+				// A declaration without a body, such as a prototype or interface method
+				if n := len(open); n > 0 && !open[n-1].started && len(braces) == open[n-1].depth {
+					open = open[:n-1]
+				}
+			}
+		}
+		// Headers whose body doesn't open by the line after the parameters weren't functions
+		if n := len(open); n > 0 && !open[n-1].started {
+			fn := open[n-1]
+			fn.parens += strings.Count(line.code, "(") - strings.Count(line.code, ")")
+			if fn.parens <= 0 && fn.headerAt < 0 {
+				fn.headerAt = i
+			}
+			if fn.headerAt >= 0 && i > fn.headerAt {
+				open = open[:n-1]
+			}
+		}
+	}
+	return s
+}
+
+// indentStructure finds functions and nesting in languages whose blocks are indented
+func indentStructure(lines []codeLine, style commentStyle) structure {
+	s := structure{method: MethodTokens}
+	var open []*openFunction
+	var controls []int // Indentation of enclosing control flow headers
+
+	closeFunctions := func(indent, line int) {
+		for len(open) > 0 && indent <= open[len(open)-1].depth {
+			fn := open[len(open)-1].function
+			fn.end = line
+			s.functions = append(s.functions, fn)
+			open = open[:len(open)-1]
+		}
+	}
+
+	last := 0
+	for i, line := range lines {
+		if line.code == "" {
+			continue
+		}
+		indent := indentation(line.raw)
+
+		// A closing "end" at the header's indentation still belongs to the block
+		closing := line.code == "end" || strings.HasPrefix(line.code, "end ")
+		if closing {
+			closeFunctions(indent-1, i)
+		} else {
+			closeFunctions(indent, last)
+		}
+		for len(controls) > 0 && indent <= controls[len(controls)-1] {
+			controls = controls[:len(controls)-1]
+		}
+		if !closing {
+			s.maxNesting = max(s.maxNesting, len(controls))
+		}
+
+		if isFunctionHeader(line.code, style) {
+			open = append(open, &openFunction{function: function{start: i, complexity: 1}, depth: indent})
+		} else if n := len(open); n > 0 {
+			open[n-1].complexity += decisions(line.code, style)
+		}
+		if controlPattern.MatchString(line.code) {
+			controls = append(controls, indent)
+		}
+		last = i
+	}
+	closeFunctions(-1, last)
+	return s
+}
+
+// indentation returns the width of a line's leading whitespace, counting tabs as four spaces
+func indentation(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
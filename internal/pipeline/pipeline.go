@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dev_profiler/internal/dto"
+	"dev_profiler/internal/services"
+)
+
+// Stage identifies a step of the audit pipeline
+type Stage string
+
+const (
+	StageAudit     Stage = "audit"
+	StageReport    Stage = "report"
+	StageDebugJSON Stage = "debug_json"
+	StageLLM       Stage = "llm"
+	StageRender    Stage = "render"
+	StageSave      Stage = "save"
+	StageDone      Stage = "done"
+)
+
+// Event describes the progress of a pipeline run
+type Event struct {
+	Stage    Stage
+	Message  string
+	Progress float64 // 0.0 to 1.0
+	Err      error   // Non-fatal problem reported by the stage
+
+	// Audit holds the detailed progress reported by the auditor during StageAudit
+	Audit *services.ProgressEvent
+}
+
+// Portion of the overall progress covered by the audit stage
+const (
+	auditProgressStart = 0.2
+	auditProgressEnd   = 0.7
+)
+
+// Auditor collects GitHub data for a user
+type Auditor interface {
+	PerformFullAudit(ctx context.Context, username string) (*dto.AuditResult, error)
+}
+
+// progressReporter is implemented by auditors that report fine-grained progress
+type progressReporter interface {
+	SetProgressCallback(callback services.ProgressFunc)
+}
+
+// Analyzer produces the LLM assessment and renders it as an HTML document
+type Analyzer interface {
+	AnalyzeGitHubData(ctx context.Context, auditResult *dto.AuditResult) (string, error)
+	ConvertMarkdownToHTML(markdownContent string, username string) string
+}
+
+// Options configures a pipeline run
+type Options struct {
+	Auditor       Auditor
+	Analyzer      Analyzer // LLM analysis is skipped when nil
+	OutputDir     string   // Defaults to DefaultOutputDir()
+	SaveDebugJSON bool
+	OpenReport    bool // Open the saved HTML report in the system browser
+	OnEvent       func(Event)
+}
+
+// Outcome holds everything produced by a pipeline run
+type Outcome struct {
+	Audit         *dto.AuditResult
+	AuditJSON     []byte
+	Markdown      string
+	HTML          string
+	ReportPath    string
+	DebugJSONPath string
+	DebugJSONErr  error
+	LLMErr        error
+}
+
+// HasReport reports whether an HTML report was generated
+func (o *Outcome) HasReport() bool {
+	return o.ReportPath != ""
+}
+
+// Run performs the audit, optional LLM analysis and report generation for a user.
+// LLM and debug JSON failures are recorded on the outcome instead of failing the run.
+// When ctx is cancelled the partial results are discarded and an error wrapping
+// context.Canceled is returned.
+func Run(ctx context.Context, username string, opts Options) (*Outcome, error) {
+	if opts.Auditor == nil {
+		return nil, fmt.Errorf("pipeline requires an auditor")
+	}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		var err error
+		outputDir, err = DefaultOutputDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	emit := func(event Event) {
+		if opts.OnEvent != nil {
+			opts.OnEvent(event)
+		}
+	}
+
+	if reporter, ok := opts.Auditor.(progressReporter); ok {
+		reporter.SetProgressCallback(func(progress services.ProgressEvent) {
+			emit(Event{
+				Stage:    StageAudit,
+				Message:  progress.Message,
+				Progress: auditProgressStart + (auditProgressEnd-auditProgressStart)*progress.Fraction,
+				Audit:    &progress,
+			})
+		})
+		defer reporter.SetProgressCallback(nil)
+	}
+
+	emit(Event{Stage: StageAudit, Message: "Collecting GitHub data...", Progress: auditProgressStart})
+	result, err := opts.Auditor.PerformFullAudit(ctx, username)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("analysis cancelled: %w", ctx.Err())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	outcome := &Outcome{Audit: result}
+
+	emit(Event{Stage: StageReport, Message: "Generating JSON report...", Progress: auditProgressEnd})
+	outcome.AuditJSON, err = json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	if opts.SaveDebugJSON {
+		emit(Event{Stage: StageDebugJSON, Message: "Saving debug JSON file...", Progress: auditProgressEnd})
+		outcome.DebugJSONPath, outcome.DebugJSONErr = SaveDebugJSON(outputDir, username, outcome.AuditJSON)
+		if outcome.DebugJSONErr != nil {
+			emit(Event{Stage: StageDebugJSON, Message: "Failed to save debug JSON file", Progress: auditProgressEnd, Err: outcome.DebugJSONErr})
+		}
+	}
+
+	if opts.Analyzer == nil {
+		emit(Event{Stage: StageDone, Message: "Analysis completed - Configure OpenAI for LLM analysis", Progress: 1.0})
+		return outcome, nil
+	}
+
+	emit(Event{Stage: StageLLM, Message: "Performing LLM analysis...", Progress: 0.8})
+	outcome.Markdown, err = opts.Analyzer.AnalyzeGitHubData(ctx, result)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("analysis cancelled: %w", ctx.Err())
+	}
+	if err != nil {
+		outcome.LLMErr = err
+		emit(Event{Stage: StageLLM, Message: "LLM analysis failed, showing JSON report", Progress: 0.8, Err: err})
+		emit(Event{Stage: StageDone, Message: "Analysis completed with JSON report", Progress: 1.0})
+		return outcome, nil
+	}
+
+	emit(Event{Stage: StageRender, Message: "Converting markdown to HTML and generating report...", Progress: 0.9})
+	outcome.HTML = opts.Analyzer.ConvertMarkdownToHTML(outcome.Markdown, username)
+
+	emit(Event{Stage: StageSave, Message: "Saving HTML report...", Progress: 0.95})
+	outcome.ReportPath, err = SaveHTMLReport(outputDir, username, outcome.HTML)
+	if err != nil {
+		return outcome, fmt.Errorf("failed to save HTML report: %w", err)
+	}
+
+	if opts.OpenReport {
+		OpenInBrowser(outcome.ReportPath)
+	}
+
+	emit(Event{Stage: StageDone, Message: "LLM analysis completed successfully - HTML report saved", Progress: 1.0})
+	return outcome, nil
+}
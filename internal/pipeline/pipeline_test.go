@@ -0,0 +1,264 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/dto"
+	"dev_profiler/internal/services"
+)
+
+type fakeAuditor struct {
+	result *dto.AuditResult
+	err    error
+	cancel context.CancelFunc // Cancels the run while the audit is in progress
+}
+
+func (f *fakeAuditor) PerformFullAudit(ctx context.Context, username string) (*dto.AuditResult, error) {
+	if f.cancel != nil {
+		f.cancel()
+		return f.result, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.result, nil
+}
+
+type fakeAnalyzer struct {
+	markdown string
+	err      error
+	called   bool
+}
+
+func (f *fakeAnalyzer) AnalyzeGitHubData(ctx context.Context, auditResult *dto.AuditResult) (string, error) {
+	f.called = true
+	return f.markdown, f.err
+}
+
+func (f *fakeAnalyzer) ConvertMarkdownToHTML(markdownContent string, username string) string {
+	return "<html>" + username + ":" + markdownContent + "</html>"
+}
+
+func newFakeAuditor() *fakeAuditor {
+	return &fakeAuditor{result: &dto.AuditResult{UserInfo: dto.UserInfo{Username: "testuser"}}}
+}
+
+func collectStages(events *[]Event) func(Event) {
+	return func(event Event) {
+		*events = append(*events, event)
+	}
+}
+
+func TestRunWithAnalyzer(t *testing.T) {
+	outputDir := t.TempDir()
+	var events []Event
+
+	outcome, err := Run(context.Background(), "testuser", Options{
+		Auditor:   newFakeAuditor(),
+		Analyzer:  &fakeAnalyzer{markdown: "# Report"},
+		OutputDir: outputDir,
+		OnEvent:   collectStages(&events),
+	})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	if !outcome.HasReport() {
+		t.Fatal("Expected an HTML report to be saved")
+	}
+	if !strings.HasPrefix(outcome.ReportPath, outputDir) {
+		t.Errorf("Report should be saved in %s, got %s", outputDir, outcome.ReportPath)
+	}
+	data, err := os.ReadFile(outcome.ReportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if string(data) != "<html>testuser:# Report</html>" {
+		t.Errorf("Unexpected report content: %s", data)
+	}
+	if outcome.DebugJSONPath != "" {
+		t.Error("Debug JSON should not be saved unless requested")
+	}
+
+	expectedStages := []Stage{StageAudit, StageReport, StageLLM, StageRender, StageSave, StageDone}
+	if len(events) != len(expectedStages) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expectedStages), len(events), events)
+	}
+	for i, stage := range expectedStages {
+		if events[i].Stage != stage {
+			t.Errorf("Event %d: expected stage %s, got %s", i, stage, events[i].Stage)
+		}
+	}
+	if events[len(events)-1].Progress != 1.0 {
+		t.Error("Final event should report full progress")
+	}
+}
+
+func TestRunWithoutAnalyzer(t *testing.T) {
+	outcome, err := Run(context.Background(), "testuser", Options{
+		Auditor:       newFakeAuditor(),
+		OutputDir:     t.TempDir(),
+		SaveDebugJSON: true,
+	})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	if outcome.HasReport() {
+		t.Error("No HTML report should be generated without an analyzer")
+	}
+	if !strings.Contains(string(outcome.AuditJSON), `"username": "testuser"`) {
+		t.Errorf("Audit JSON should contain the username, got %s", outcome.AuditJSON)
+	}
+	if outcome.DebugJSONPath == "" {
+		t.Fatal("Debug JSON should be saved when requested")
+	}
+	if _, err := os.Stat(outcome.DebugJSONPath); err != nil {
+		t.Errorf("Debug JSON file should exist: %v", err)
+	}
+}
+
+func TestRunLLMFailureIsNotFatal(t *testing.T) {
+	var events []Event
+	llmErr := errors.New("model unavailable")
+
+	outcome, err := Run(context.Background(), "testuser", Options{
+		Auditor:   newFakeAuditor(),
+		Analyzer:  &fakeAnalyzer{err: llmErr},
+		OutputDir: t.TempDir(),
+		OnEvent:   collectStages(&events),
+	})
+	if err != nil {
+		t.Fatalf("Run() should not fail when the LLM analysis fails: %v", err)
+	}
+
+	if !errors.Is(outcome.LLMErr, llmErr) {
+		t.Errorf("Expected LLM error to be recorded, got %v", outcome.LLMErr)
+	}
+	if outcome.HasReport() {
+		t.Error("No HTML report should be generated when the LLM analysis fails")
+	}
+	if len(outcome.AuditJSON) == 0 {
+		t.Error("Audit JSON should still be available")
+	}
+
+	foundWarning := false
+	for _, event := range events {
+		if event.Stage == StageLLM && event.Err != nil {
+			foundWarning = true
+		}
+	}
+	if !foundWarning {
+		t.Error("Expected an LLM event carrying the error")
+	}
+}
+
+func TestRunAuditFailure(t *testing.T) {
+	auditErr := errors.New("user not found")
+
+	outcome, err := Run(context.Background(), "testuser", Options{
+		Auditor:   &fakeAuditor{err: auditErr},
+		OutputDir: t.TempDir(),
+	})
+	if err == nil {
+		t.Fatal("Run() should fail when the audit fails")
+	}
+	if !errors.Is(err, auditErr) {
+		t.Errorf("Expected wrapped audit error, got %v", err)
+	}
+	if outcome != nil {
+		t.Error("Outcome should be nil when the audit fails")
+	}
+}
+
+func TestRunRequiresAuditor(t *testing.T) {
+	if _, err := Run(context.Background(), "testuser", Options{OutputDir: t.TempDir()}); err == nil {
+		t.Error("Run() should fail without an auditor")
+	}
+}
+
+func TestRunCancelledDuringAudit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auditor := newFakeAuditor()
+	auditor.cancel = cancel
+	analyzer := &fakeAnalyzer{markdown: "# Report"}
+	outputDir := t.TempDir()
+
+	outcome, err := Run(ctx, "testuser", Options{
+		Auditor:       auditor,
+		Analyzer:      analyzer,
+		OutputDir:     outputDir,
+		SaveDebugJSON: true,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if outcome != nil {
+		t.Error("Partial results should be discarded on cancellation")
+	}
+	if analyzer.called {
+		t.Error("LLM analysis should not run after cancellation")
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("Failed to read output dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("No files should be written after cancellation, found %d", len(entries))
+	}
+}
+
+type reportingAuditor struct {
+	fakeAuditor
+	callback services.ProgressFunc
+}
+
+func (r *reportingAuditor) SetProgressCallback(callback services.ProgressFunc) {
+	r.callback = callback
+}
+
+func (r *reportingAuditor) PerformFullAudit(ctx context.Context, username string) (*dto.AuditResult, error) {
+	r.callback(services.ProgressEvent{Kind: services.ProgressRepoDone, Message: "halfway", Fraction: 0.5})
+	return r.fakeAuditor.PerformFullAudit(ctx, username)
+}
+
+func TestRunForwardsAuditProgress(t *testing.T) {
+	auditor := &reportingAuditor{fakeAuditor: *newFakeAuditor()}
+	var events []Event
+
+	_, err := Run(context.Background(), "testuser", Options{
+		Auditor:   auditor,
+		OutputDir: t.TempDir(),
+		OnEvent:   collectStages(&events),
+	})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	var forwarded *Event
+	for i := range events {
+		if events[i].Audit != nil {
+			forwarded = &events[i]
+		}
+	}
+	if forwarded == nil {
+		t.Fatal("Expected audit progress to be forwarded")
+	}
+	if forwarded.Stage != StageAudit || forwarded.Message != "halfway" {
+		t.Errorf("Unexpected forwarded event: %+v", forwarded)
+	}
+	expected := auditProgressStart + (auditProgressEnd-auditProgressStart)*0.5
+	if forwarded.Progress != expected {
+		t.Errorf("Expected progress %f, got %f", expected, forwarded.Progress)
+	}
+	if auditor.callback != nil {
+		t.Error("Progress callback should be removed after the run")
+	}
+}
@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DefaultOutputDir returns the directory reports are saved to when none is configured
+func DefaultOutputDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, "github_reports"), nil
+}
+
+// SaveHTMLReport writes the HTML report to a timestamped file in dir and returns its path
+func SaveHTMLReport(dir, username, htmlContent string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create reports directory: %w", err)
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_github_assessment_%s.html", username, timestamp)
+	filePath := filepath.Join(dir, filename)
+
+	if err := os.WriteFile(filePath, []byte(htmlContent), 0644); err != nil {
+		return "", fmt.Errorf("failed to write HTML report: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// SaveDebugJSON writes the raw audit JSON to a timestamped file in dir and returns its path
+func SaveDebugJSON(dir, username string, jsonData []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create reports directory: %w", err)
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_github_audit_debug_%s.json", username, timestamp)
+	filePath := filepath.Join(dir, filename)
+
+	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write debug JSON file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// OpenInBrowser tries to open a file in the system browser without blocking
+func OpenInBrowser(filePath string) {
+	go func() {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "linux":
+			cmd = exec.Command("xdg-open", filePath)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", filePath)
+		case "darwin":
+			cmd = exec.Command("open", filePath)
+		default:
+			return // Unsupported OS
+		}
+
+		// Silently fail - user can manually open the file
+		_ = cmd.Run()
+	}()
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+
+	"dev_profiler/internal/dto"
+)
+
+// collector gathers the profile, repositories, languages and authored commits for an audit.
+// File sampling always uses the REST API.
+type collector interface {
+	GetUser(ctx context.Context, username string) (*dto.UserInfo, error)
+	ListRepositories(ctx context.Context, username string) ([]*dto.Repository, error)
+	GetRepositoryLanguages(ctx context.Context, username, repoName string) ([]string, error)
+	GetRepositoryCommits(ctx context.Context, username, repoName string, count int) ([]*dto.CommitDetail, error)
+
+	// prefetchCommits is called with the repositories selected for analysis before they are analyzed
+	prefetchCommits(ctx context.Context, username string, repos []*dto.Repository, count int) error
+
+	// reset forgets the data kept from a previous audit; it is called when an audit starts
+	reset()
+}
+
+// restCollector collects data with one REST API call per resource
+type restCollector struct {
+	*GitHubService
+}
+
+// prefetchCommits does nothing, commits are listed per repository
+func (c restCollector) prefetchCommits(ctx context.Context, username string, repos []*dto.Repository, count int) error {
+	return nil
+}
+
+// reset does nothing, nothing is kept between calls
+func (c restCollector) reset() {}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// searchPageSize is the largest page size supported by the search API
+const searchPageSize = 100
+
+// Overall audit completion reported while collecting activity outside the user's repositories
+const activityProgress = 0.08
+
+// userActivity holds what the user did on GitHub besides pushing to their own repositories
+type userActivity struct {
+	pullRequests []*dto.PullRequest
+	codeReviews  []*dto.CodeReview
+	issues       dto.IssueActivity
+	external     []*dto.ExternalContribution
+	errors       []string // Collections that failed; the audit continues without them
+}
+
+// collectActivity collects the user's pull requests, code reviews, issue participation and
+// contributions to repositories owned by others.
+// Failures are recorded as messages so the rest of the audit can continue.
+func (s *GitHubService) collectActivity(ctx context.Context, username string) *userActivity {
+	activity := &userActivity{}
+	record := func(err error) {
+		if err != nil {
+			activity.errors = append(activity.errors, err.Error())
+		}
+	}
+
+	if s.config.PullRequestCount > 0 {
+		var err error
+		activity.pullRequests, err = s.CollectPullRequests(ctx, username, s.config.PullRequestCount)
+		record(err)
+		activity.codeReviews, err = s.CollectCodeReviews(ctx, username, s.config.PullRequestCount)
+		record(err)
+	}
+
+	if s.config.IssueCount > 0 {
+		issues, err := s.CollectIssueActivity(ctx, username, s.config.IssueCount)
+		record(err)
+		if issues != nil {
+			activity.issues = *issues
+		}
+	}
+
+	if s.config.ExternalRepoCount > 0 {
+		var err error
+		activity.external, err = s.CollectExternalContributions(ctx, username, s.config.ExternalRepoCount, s.config.SampleExternalDiffs)
+		record(err)
+	}
+
+	return activity
+}
+
+// searchIssues returns up to count issues or pull requests matching the query, most recently created first
+func (s *GitHubService) searchIssues(ctx context.Context, query string, count int) ([]*github.Issue, error) {
+	opt := &github.SearchOptions{
+		Sort:        "created",
+		Order:       "desc",
+		ListOptions: github.ListOptions{PerPage: min(count, searchPageSize)},
+	}
+
+	var issues []*github.Issue
+	for len(issues) < count {
+		var result *github.IssuesSearchResult
+		var resp *github.Response
+		err := s.callWithRetry(ctx, "", "search", func() (r *github.Response, err error) {
+			result, r, err = s.client.Search.Issues(ctx, query, opt)
+			resp = r
+			return r, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, result.Issues...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	if len(issues) > count {
+		issues = issues[:count]
+	}
+	return issues, nil
+}
+
+// issueRepository returns the owner and name of the repository an issue belongs to
+func issueRepository(issue *github.Issue) (string, string) {
+	if repo := issue.GetRepository(); repo != nil {
+		return repo.GetOwner().GetLogin(), repo.GetName()
+	}
+
+	// Search results only link to the repository, e.g. https://api.github.com/repos/owner/name
+	_, path, _ := strings.Cut(issue.GetRepositoryURL(), "/repos/")
+	owner, name, _ := strings.Cut(path, "/")
+	return owner, name
+}
+
+// truncateText shortens text to at most maxLength runes
+func truncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength]) + "..."
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Forks with more commits made in the fork than this are considered significant
+const significantForkCommits = 10
+
+// Maximum number of compare pages inspected when counting commits made in a fork
+const maxComparePages = 5
+
+// Commits are counted in at most this many times as many repositories as are sampled for analysis.
+// Counting costs a call per repository and several per fork, and older repositories are rarely sampled.
+const commitCountCandidateFactor = 3
+
+// commitCountCandidates returns the repositories whose commits are counted: those that may be
+// selected for analysis, the most recently pushed first, up to commitCountCandidateFactor times
+// the number of sampled repositories
+func (s *GitHubService) commitCountCandidates(repos []*dto.Repository, cutoff time.Time) []*dto.Repository {
+	var candidates []*dto.Repository
+	for _, repo := range repos {
+		if s.isAnalysisCandidate(repo, cutoff) {
+			candidates = append(candidates, repo)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastPush(candidates[i]).After(lastPush(candidates[j]))
+	})
+	return candidates[:min(len(candidates), s.config.SampledRepoCount*commitCountCandidateFactor)]
+}
+
+// countUserCommits fills in the number of commits the user authored in each repository. Nothing
+// else calls the API meanwhile, so the calls made are recorded as the cost of counting. Counts
+// stay at zero, or for forks include inherited commits, when a repository can't be inspected;
+// the failures are returned as messages in repository order so the audit can report them.
+func (s *GitHubService) countUserCommits(ctx context.Context, username string, repos []*dto.Repository) ([]string, error) {
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressRepositories,
+		Message:  fmt.Sprintf("Counting commits by %s in %d repositories...", username, len(repos)),
+		Fraction: 0.07,
+	})
+
+	callsBefore := s.APIUsage().Calls
+	errs := make([]error, len(repos))
+	err := runBounded(ctx, len(repos), s.config.RepoConcurrency, func(i int) {
+		errs[i] = s.countRepositoryCommits(ctx, username, repos[i])
+	})
+	s.recordCommitCountCalls(s.APIUsage().Calls - callsBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	return failures, nil
+}
+
+// countRepositoryCommits counts the user's commits in a repository. For forks, only commits
+// made in the fork are counted as UserCommits; commits inherited from the upstream
+// repository are recorded as UpstreamCommits.
+func (s *GitHubService) countRepositoryCommits(ctx context.Context, username string, repo *dto.Repository) error {
+	total, err := s.countCommitsByAuthor(ctx, username, repo.Name)
+	if err != nil {
+		return err
+	}
+
+	if !repo.Fork {
+		repo.UserCommits = total
+		return nil
+	}
+
+	forkCommits, err := s.countForkCommits(ctx, username, repo)
+	if err != nil {
+		// Without the upstream comparison all commits are attributed to the fork
+		repo.UserCommits = total
+		return err
+	}
+
+	repo.UserCommits = forkCommits
+	repo.UpstreamCommits = max(total-forkCommits, 0)
+	return nil
+}
+
+// countCommitsByAuthor returns the number of commits by the user on the default branch.
+// Listing a single commit per page makes the last page number equal to the commit count.
+func (s *GitHubService) countCommitsByAuthor(ctx context.Context, username, repoName string) (int, error) {
+	opt := &github.CommitsListOptions{
+		Author:      username,
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+
+	var commits []*github.RepositoryCommit
+	var resp *github.Response
+	err := s.callWithRetry(ctx, repoName, "commit count", func() (r *github.Response, err error) {
+		commits, r, err = s.client.Repositories.ListCommits(ctx, username, repoName, opt)
+		resp = r
+		return r, err
+	})
+	if err != nil {
+		// Empty repositories report a conflict instead of an empty list
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to count commits for %s/%s: %w", username, repoName, err)
+	}
+
+	if resp.LastPage > 0 {
+		return resp.LastPage, nil
+	}
+	return len(commits), nil
+}
+
+// countForkCommits returns the number of commits by the user that the fork has on top of its upstream repository
+func (s *GitHubService) countForkCommits(ctx context.Context, username string, repo *dto.Repository) (int, error) {
+	var fork *github.Repository
+	err := s.callWithRetry(ctx, repo.Name, "fork details", func() (resp *github.Response, err error) {
+		fork, resp, err = s.client.Repositories.Get(ctx, username, repo.Name)
+		return resp, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get fork %s/%s: %w", username, repo.Name, err)
+	}
+
+	parent := fork.GetParent()
+	if parent == nil {
+		return 0, fmt.Errorf("fork %s/%s has no upstream repository", username, repo.Name)
+	}
+	if repo.ForkSource == "" {
+		repo.ForkSource = parent.GetFullName()
+	}
+
+	// Compare the upstream default branch with the fork's default branch
+	head := fmt.Sprintf("%s:%s", fork.GetOwner().GetLogin(), fork.GetDefaultBranch())
+	opt := &github.ListOptions{PerPage: 100}
+	count := 0
+	for page := 0; page < maxComparePages; page++ {
+		var comparison *github.CommitsComparison
+		var resp *github.Response
+		err := s.callWithRetry(ctx, repo.Name, "fork comparison", func() (r *github.Response, err error) {
+			comparison, r, err = s.client.Repositories.CompareCommits(ctx, parent.GetOwner().GetLogin(), parent.GetName(), parent.GetDefaultBranch(), head, opt)
+			resp = r
+			return r, err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to compare fork %s/%s with %s: %w", username, repo.Name, parent.GetFullName(), err)
+		}
+
+		if comparison.GetAheadBy() == 0 {
+			return 0, nil
+		}
+		for _, commit := range comparison.Commits {
+			if strings.EqualFold(commit.GetAuthor().GetLogin(), username) {
+				count++
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return count, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"dev_profiler/internal/dto"
+)
+
+// maxHunksPerFile limits the hunks sampled from one file so the budget covers several files
+const maxHunksPerFile = 2
+
+// diffHunk is a hunk from the patch of a file changed in one of the user's commits
+type diffHunk struct {
+	file  *dto.DiffSample
+	text  string
+	added int
+	order int // Position in the commit and patch order
+}
+
+// sampleCommitDiffs selects representative hunks from the patches of the user's commits, preferring
+// hunks that add the most lines, until the budget in characters is spent. Samples keep the commit order.
+func sampleCommitDiffs(commits []*dto.CommitDetail, budget int) []*dto.DiffSample {
+	// Long hunks are cut so that a single large change can't take the whole budget
+	maxHunkLength := budget / 2
+
+	var hunks []diffHunk
+	for _, commit := range commits {
+		for _, file := range commit.Patches {
+			for _, text := range splitHunks(file.Patch) {
+				hunks = append(hunks, diffHunk{
+					file:  file,
+					text:  cutLines(text, maxHunkLength),
+					added: countAddedLines(text),
+					order: len(hunks),
+				})
+			}
+		}
+	}
+	sort.SliceStable(hunks, func(i, j int) bool {
+		return hunks[i].added > hunks[j].added
+	})
+
+	selected := make(map[*dto.DiffSample][]diffHunk)
+	remaining := budget
+	for _, hunk := range hunks {
+		if hunk.added == 0 || hunk.text == "" || len(hunk.text) > remaining || len(selected[hunk.file]) >= maxHunksPerFile {
+			continue
+		}
+		selected[hunk.file] = append(selected[hunk.file], hunk)
+		remaining -= len(hunk.text)
+	}
+
+	var samples []*dto.DiffSample
+	for _, commit := range commits {
+		for _, file := range commit.Patches {
+			chosen := selected[file]
+			if len(chosen) == 0 {
+				continue
+			}
+			sort.Slice(chosen, func(i, j int) bool {
+				return chosen[i].order < chosen[j].order
+			})
+
+			texts := make([]string, len(chosen))
+			for i, hunk := range chosen {
+				texts[i] = hunk.text
+			}
+			sample := *file
+			sample.Patch = strings.Join(texts, "\n")
+			samples = append(samples, &sample)
+		}
+	}
+	return samples
+}
+
+// splitHunks splits a unified diff patch into hunks, each starting with its @@ header
+func splitHunks(patch string) []string {
+	var hunks []string
+	var current []string
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "@@") && len(current) > 0 {
+			hunks = append(hunks, strings.Join(current, "\n"))
+			current = nil
+		}
+		if strings.HasPrefix(line, "@@") || len(current) > 0 {
+			current = append(current, line)
+		}
+	}
+	if len(current) > 0 {
+		hunks = append(hunks, strings.Join(current, "\n"))
+	}
+	return hunks
+}
+
+// cutLines returns the longest prefix of whole lines that fits in limit bytes
+func cutLines(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	end := strings.LastIndex(text[:limit+1], "\n")
+	if end < 0 {
+		return ""
+	}
+	return text[:end]
+}
+
+// countAddedLines returns the number of lines a hunk adds
+func countAddedLines(hunk string) int {
+	added := 0
+	for _, line := range strings.Split(hunk, "\n") {
+		if strings.HasPrefix(line, "+") {
+			added++
+		}
+	}
+	return added
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
+)
+
+func TestSplitHunks(t *testing.T) {
+	patch := "@@ -1,2 +1,3 @@\n context\n+added\n@@ -10 +11 @@\n-removed\n+replaced"
+
+	hunks := splitHunks(patch)
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d: %q", len(hunks), hunks)
+	}
+	if hunks[0] != "@@ -1,2 +1,3 @@\n context\n+added" {
+		t.Errorf("Unexpected first hunk: %q", hunks[0])
+	}
+	if countAddedLines(hunks[1]) != 1 {
+		t.Errorf("Expected 1 added line in %q", hunks[1])
+	}
+}
+
+func TestSampleCommitDiffs(t *testing.T) {
+	small := "@@ -1 +1 @@\n+one"
+	large := "@@ -1 +1,3 @@\n+one\n+two\n+three"
+	removal := "@@ -1,3 +0,0 @@\n-one\n-two\n-three"
+
+	commits := []*dto.CommitDetail{
+		{SHA: "first", Patches: []*dto.DiffSample{
+			{SHA: "first", Path: "a.go", Patch: small + "\n" + removal},
+		}},
+		{SHA: "second", Patches: []*dto.DiffSample{
+			{SHA: "second", Path: "b.go", Patch: large + "\n" + small + "\n" + small},
+		}},
+	}
+
+	testCases := []struct {
+		name     string
+		budget   int
+		expected []string // path: patch
+	}{
+		{"everything fits", 1000, []string{"a.go: " + small, "b.go: " + large + "\n" + small}},
+		{"largest hunk first", 2 * len(large), []string{"a.go: " + small, "b.go: " + large}},
+		{"long hunks cut at line boundaries", 45, []string{"a.go: " + small, "b.go: @@ -1 +1,3 @@\n+one"}},
+		{"nothing fits", 20, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			samples := sampleCommitDiffs(commits, tc.budget)
+			var got []string
+			for _, sample := range samples {
+				got = append(got, sample.Path+": "+sample.Patch)
+			}
+			if strings.Join(got, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPerformFullAuditSamplesDiffs(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go", "b.go"}},
+	})
+
+	cfg := config.DefaultGitHubConfig()
+	cfg.SampleSource = config.SampleSourceDiffs
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	if len(result.FileAnalysis) != 0 {
+		t.Errorf("Whole files should not be sampled in diff mode, got %d", len(result.FileAnalysis))
+	}
+	if len(result.DiffSamples) != 2 {
+		t.Fatalf("Expected a diff sample per commit, got %d", len(result.DiffSamples))
+	}
+	if sample := result.DiffSamples[0]; sample.Repo != "alpha" || sample.SHA != "alpha-0" || sample.Language != "Go" || !strings.Contains(sample.Patch, "+// alpha-0") {
+		t.Errorf("Unexpected diff sample: %+v", sample)
+	}
+	if counts := result.AnalysisSummary.SampledLanguages; counts["Go"] != 2 {
+		t.Errorf("Expected two Go samples, got %v", counts)
+	}
+	if outcome := result.AnalysisSummary.RepoOutcomes[0]; outcome.Status != dto.RepoAnalyzed {
+		t.Errorf("Expected alpha to be analyzed, got %+v", outcome)
+	}
+}
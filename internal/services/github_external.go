@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Limits for discovering and sampling contributions to repositories owned by others
+const (
+	externalCandidateFactor = 3    // Candidates looked up per reported repository, ranked by contribution size
+	externalDiffRepos       = 3    // Top repositories whose patches are sampled
+	externalDiffCommits     = 3    // Commits sampled per repository
+	maxPatchLength          = 2000 // Characters kept from each sampled patch
+)
+
+// CollectExternalContributions finds repositories owned by others where the user has merged pull requests
+// or authored commits, and returns the count most significant ones
+func (s *GitHubService) CollectExternalContributions(ctx context.Context, username string, count int, sampleDiffs bool) ([]*dto.ExternalContribution, error) {
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressActivity,
+		Message:  fmt.Sprintf("Searching contributions by %s to other repositories...", username),
+		Fraction: activityProgress,
+	})
+
+	byRepo := make(map[string]*dto.ExternalContribution)
+	var contributions []*dto.ExternalContribution
+	contribution := func(owner, name string) *dto.ExternalContribution {
+		key := repoKey(owner, name)
+		if c, ok := byRepo[key]; ok {
+			return c
+		}
+		c := &dto.ExternalContribution{Repo: owner + "/" + name}
+		byRepo[key] = c
+		contributions = append(contributions, c)
+		return c
+	}
+
+	// is:public keeps out private repositories the token can see, whose diffs could otherwise be sampled
+	mergedPRs, err := s.searchIssues(ctx, fmt.Sprintf("type:pr author:%s is:merged -user:%s is:public", username, username), searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search merged pull requests by %s: %w", username, err)
+	}
+	for _, pr := range mergedPRs {
+		contribution(issueRepository(pr)).MergedPullRequests++
+	}
+
+	commits, err := s.searchCommits(ctx, fmt.Sprintf("author:%s -user:%s is:public", username, username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search commits by %s: %w", username, err)
+	}
+	shas := make(map[string][]string)
+	for _, commit := range commits {
+		repo := commit.GetRepository()
+		c := contribution(repo.GetOwner().GetLogin(), repo.GetName())
+		c.Commits++
+		shas[c.Repo] = append(shas[c.Repo], commit.GetSHA())
+	}
+
+	// Only look up the largest contributions, then rank them with their star counts
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributionSize(contributions[i]) > contributionSize(contributions[j])
+	})
+	if len(contributions) > count*externalCandidateFactor {
+		contributions = contributions[:count*externalCandidateFactor]
+	}
+
+	err = runBounded(ctx, len(contributions), s.config.RepoConcurrency, func(i int) {
+		c := contributions[i]
+		owner, name, _ := strings.Cut(c.Repo, "/")
+
+		var repo *github.Repository
+		err := s.callWithRetry(ctx, name, "external repository", func() (resp *github.Response, err error) {
+			repo, resp, err = s.client.Repositories.Get(ctx, owner, name)
+			return resp, err
+		})
+		if err == nil {
+			c.Description = repo.GetDescription()
+			c.Language = repo.GetLanguage()
+			c.Stars = repo.GetStargazersCount()
+		}
+		c.Significance = contributionSignificance(c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].Significance > contributions[j].Significance
+	})
+	if len(contributions) > count {
+		contributions = contributions[:count]
+	}
+
+	if sampleDiffs {
+		top := contributions[:min(len(contributions), externalDiffRepos)]
+		err = runBounded(ctx, len(top), s.config.RepoConcurrency, func(i int) {
+			top[i].DiffSamples = s.sampleDiffs(ctx, top[i].Repo, shas[top[i].Repo])
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return contributions, nil
+}
+
+// searchCommits returns the most recent commits matching the query from a single page of the commit search API
+func (s *GitHubService) searchCommits(ctx context.Context, query string) ([]*github.CommitResult, error) {
+	opt := &github.SearchOptions{
+		Sort:        "author-date",
+		Order:       "desc",
+		ListOptions: github.ListOptions{PerPage: searchPageSize},
+	}
+
+	var result *github.CommitsSearchResult
+	err := s.callWithRetry(ctx, "", "commit search", func() (resp *github.Response, err error) {
+		result, resp, err = s.client.Search.Commits(ctx, query, opt)
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Commits, nil
+}
+
+// sampleDiffs returns the patches of code files changed by the first commits of a repository.
+// Commits that can't be fetched are skipped.
+func (s *GitHubService) sampleDiffs(ctx context.Context, fullName string, shas []string) []*dto.DiffSample {
+	owner, name, _ := strings.Cut(fullName, "/")
+	var samples []*dto.DiffSample
+	for _, sha := range shas[:min(len(shas), externalDiffCommits)] {
+		var commit *github.RepositoryCommit
+		err := s.callWithRetry(ctx, name, "commit diff", func() (resp *github.Response, err error) {
+			commit, resp, err = s.client.Repositories.GetCommit(ctx, owner, name, sha, nil)
+			return resp, err
+		})
+		if err != nil {
+			continue
+		}
+
+		for _, file := range commit.Files {
+			if file.GetPatch() == "" || !s.isSampleCandidate(file.GetFilename()) {
+				continue
+			}
+			samples = append(samples, &dto.DiffSample{
+				Repo:      fullName,
+				SHA:       sha,
+				Path:      file.GetFilename(),
+				Language:  s.languages.Detect(file.GetFilename(), ""),
+				Additions: file.GetAdditions(),
+				Deletions: file.GetDeletions(),
+				Patch:     truncateText(file.GetPatch(), maxPatchLength),
+			})
+		}
+	}
+	return samples
+}
+
+// contributionSize weighs merged pull requests higher than individual commits
+func contributionSize(c *dto.ExternalContribution) int {
+	return 3*c.MergedPullRequests + c.Commits
+}
+
+// contributionSignificance scales the contribution size by the repository's popularity
+func contributionSignificance(c *dto.ExternalContribution) float64 {
+	score := float64(contributionSize(c)) * (1 + math.Log10(1+float64(c.Stars)))
+	return math.Round(score*100) / 100
+}
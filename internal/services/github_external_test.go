@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
+)
+
+func TestContributionSignificance(t *testing.T) {
+	testCases := []struct {
+		contribution dto.ExternalContribution
+		expected     float64
+	}{
+		{dto.ExternalContribution{Commits: 2}, 2},
+		{dto.ExternalContribution{MergedPullRequests: 1, Commits: 1}, 4},
+		{dto.ExternalContribution{Commits: 1, Stars: 99}, 3},
+		{dto.ExternalContribution{MergedPullRequests: 2, Stars: 9999}, 30},
+	}
+
+	for _, tc := range testCases {
+		if got := contributionSignificance(&tc.contribution); got != tc.expected {
+			t.Errorf("contributionSignificance(%+v) = %v, expected %v", tc.contribution, got, tc.expected)
+		}
+	}
+}
+
+func TestPerformFullAuditFindsExternalContributions(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1}})
+	server.pullRequests = []fakePullRequest{
+		{repo: "golang/go", number: 1, author: "octocat", merged: true},
+		{repo: "small/tool", number: 2, author: "octocat", merged: true},
+		{repo: "small/tool", number: 3, author: "octocat", merged: true},
+		{repo: "closed/project", number: 4, author: "octocat"},
+		{repo: "acme/internal", number: 5, author: "octocat", merged: true, private: true},
+	}
+	server.externalCommits = []fakeExternalCommit{
+		{repo: "golang/go", sha: "abc", path: "src/net/http/server.go", patch: "@@ -1 +1 @@\n-old\n+new"},
+		{repo: "small/tool", sha: "def", path: "main.go", patch: "@@ -1 +1 @@\n+tool"},
+		{repo: "tiny/lib", sha: "123", path: "lib.go", patch: "@@ -1 +1 @@\n+lib"},
+		{repo: "acme/internal", sha: "456", path: "secret.go", patch: "@@ -1 +1 @@\n+secret", private: true},
+	}
+	server.stars = map[string]int{"golang/go": 120000, "small/tool": 9, "tiny/lib": 0, "acme/internal": 500}
+
+	testCases := []struct {
+		name          string
+		count         int
+		sampleDiffs   bool
+		expectedRepos []string
+	}{
+		{"ranked by significance", 10, false, []string{"golang/go", "small/tool", "tiny/lib"}},
+		{"limited count", 1, true, []string{"golang/go"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.DefaultGitHubConfig()
+			cfg.ExternalRepoCount = tc.count
+			cfg.SampleExternalDiffs = tc.sampleDiffs
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			contributions := result.ExternalContributions
+			if len(contributions) != len(tc.expectedRepos) {
+				t.Fatalf("Expected %d contributions, got %d", len(tc.expectedRepos), len(contributions))
+			}
+			for i, repo := range tc.expectedRepos {
+				if contributions[i].Repo != repo {
+					t.Errorf("Contribution %d: expected %s, got %s", i, repo, contributions[i].Repo)
+				}
+			}
+
+			golang := contributions[0]
+			if golang.MergedPullRequests != 1 || golang.Commits != 1 || golang.Stars != 120000 {
+				t.Errorf("Unexpected contribution: %+v", golang)
+			}
+			if !tc.sampleDiffs {
+				if len(golang.DiffSamples) != 0 {
+					t.Errorf("Diffs should only be sampled when enabled")
+				}
+				return
+			}
+			if len(golang.DiffSamples) != 1 || golang.DiffSamples[0].Path != "src/net/http/server.go" || golang.DiffSamples[0].SHA != "abc" {
+				t.Errorf("Expected the patch of the code file only, got %+v", golang.DiffSamples)
+			}
+		})
+	}
+}
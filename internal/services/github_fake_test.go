@@ -0,0 +1,649 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/config"
+)
+
+// fakeGitHubServer is a fake GitHub REST and GraphQL API that counts the requests it serves
+type fakeGitHubServer struct {
+	*httptest.Server
+	requests        int32
+	graphQLQueries  int32
+	contentRequests int32
+	viewer          string // Login of the token owner, defaults to the audited user
+	pullRequests    []fakePullRequest
+	issues          []fakeIssue
+
+	// Commits by the user in repositories owned by others, and the star counts of those repositories
+	externalCommits []fakeExternalCommit
+	stars           map[string]int
+
+	searchMu      sync.Mutex
+	searchQueries []string // Queries received by the search API, in order
+}
+
+// searched returns the queries the search API received so far
+func (f *fakeGitHubServer) searched() []string {
+	f.searchMu.Lock()
+	defer f.searchMu.Unlock()
+	return append([]string(nil), f.searchQueries...)
+}
+
+// recordSearch records a query received by the search API
+func (f *fakeGitHubServer) recordSearch(query string) {
+	f.searchMu.Lock()
+	defer f.searchMu.Unlock()
+	f.searchQueries = append(f.searchQueries, query)
+}
+
+// fakeExternalCommit describes a commit served by the fake commit search API
+type fakeExternalCommit struct {
+	repo    string // owner/name
+	sha     string
+	path    string
+	patch   string
+	private bool // In a private repository the token can see, found only by searches without is:public
+}
+
+// fakeIssue describes an issue served by the fake search API
+type fakeIssue struct {
+	repo       string // owner/name
+	number     int
+	title      string
+	author     string
+	labels     []string
+	commenters []string
+	private    bool // In a private repository the token can see, found only by searches without is:public
+}
+
+// fakePullRequest describes a pull request in a repository outside the audited user's account
+type fakePullRequest struct {
+	repo      string // owner/name
+	number    int
+	author    string
+	merged    bool
+	additions int
+	reviews   []fakeReview
+	missing   bool // Found by search, but the details and reviews endpoints return 404
+	private   bool // In a private repository the token can see, found only by searches without is:public
+}
+
+// fakeReview describes a review left on a fake pull request
+type fakeReview struct {
+	user  string
+	state string
+	body  string
+}
+
+// Page sizes used by the fake GraphQL API to exercise pagination
+const (
+	fakeRepositoryPageSize = 2
+	fakeCommitPageSize     = 3
+)
+
+// fakeRepo describes a repository served by the fake GitHub API
+type fakeRepo struct {
+	name    string
+	fork    bool
+	private bool
+	commits int
+	files   []string
+
+	// Commits made in a fork on top of its upstream repository, the rest are inherited
+	forkCommits  int
+	upstreamGone bool // The upstream repository was deleted, so comparing the fork with it fails
+
+	empty        bool // The repository has no commits, so commit and tree requests conflict
+	missingTree  bool // Tree requests fail with 404
+	archived     bool
+	stalePush    bool // Last pushed years ago although recently updated, as happens when it is starred
+	collaborator bool // Owned by another account the user collaborates with, listed only when asked for
+
+	// Share of each file written by the user in tenths, files not listed are written entirely by the user
+	ownership map[string]int
+
+	// Content of files by path, files not listed hold a generated Go file
+	contents map[string]string
+
+	// Git authors, as "Name <email>", of recent commits not linked to any account. They are newer
+	// than the user's commits and only listed when commits aren't filtered by author.
+	unlinked []string
+}
+
+// fakePushedAt returns the last push time of the repository at the given listing position
+func fakePushedAt(repo fakeRepo, position int, updated time.Time) time.Time {
+	if repo.stalePush {
+		return updated.AddDate(-10, 0, 0)
+	}
+	return updated.Add(-time.Duration(position) * time.Hour)
+}
+
+// fakeFileAuthors returns the authors of the ten lines, or last ten commits, of a file
+func fakeFileAuthors(username string, repo fakeRepo, path string) []string {
+	owned, ok := repo.ownership[path]
+	if !ok {
+		owned = 10
+	}
+	authors := make([]string, 10)
+	for i := range authors {
+		authors[i] = "someone"
+		if i < owned {
+			authors[i] = username
+		}
+	}
+	return authors
+}
+
+// newFakeGitHubServer serves the REST and GraphQL endpoints used by PerformFullAudit for the given user
+func newFakeGitHubServer(t *testing.T, username string, repos []fakeRepo) *fakeGitHubServer {
+	t.Helper()
+
+	byName := make(map[string]fakeRepo, len(repos))
+	for _, repo := range repos {
+		byName[repo.name] = repo
+	}
+	updated := time.Now().Add(-24 * time.Hour)
+
+	writeJSON := func(w http.ResponseWriter, value interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(value); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}
+
+	fake := &fakeGitHubServer{viewer: username}
+	listRepos := func(w http.ResponseWriter, includePrivate, includeCollaborator bool) {
+		var list []map[string]interface{}
+		for i, repo := range repos {
+			if repo.private && !includePrivate || repo.collaborator && !includeCollaborator {
+				continue
+			}
+			list = append(list, map[string]interface{}{
+				"name":              repo.name,
+				"fork":              repo.fork,
+				"private":           repo.private,
+				"archived":          repo.archived,
+				"updated_at":        updated.Add(-time.Duration(i) * time.Hour),
+				"pushed_at":         fakePushedAt(repo, i, updated),
+				"topics":            []string{"cli", repo.name},
+				"license":           map[string]string{"spdx_id": "MIT"},
+				"size":              120,
+				"forks_count":       3,
+				"watchers_count":    2,
+				"open_issues_count": 4,
+				"homepage":          "https://example.com/" + repo.name,
+				"default_branch":    "main",
+			})
+		}
+		writeJSON(w, list)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"login": fake.viewer})
+	})
+	mux.HandleFunc("/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		// Without an affiliation, repositories of collaborators and organizations are listed too
+		affiliation := r.URL.Query().Get("affiliation")
+		listRepos(w, true, affiliation == "" || strings.Contains(affiliation, "collaborator"))
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fake.graphQLQueries, 1)
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"data": fakeGraphQLData(username, repos, updated, req.Query, req.Variables)})
+	})
+	mux.HandleFunc("/users/"+username, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"login": username, "public_repos": len(repos), "plan": map[string]string{"name": "pro"}})
+	})
+	mux.HandleFunc("/users/"+username+"/repos", func(w http.ResponseWriter, r *http.Request) {
+		listType := r.URL.Query().Get("type")
+		listRepos(w, false, listType == "all" || listType == "member")
+	})
+	mux.HandleFunc("/repos/"+username+"/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/"+username+"/"), "/", 2)
+		repo, ok := byName[parts[0]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if len(parts) < 2 {
+			details := map[string]interface{}{
+				"name":           repo.name,
+				"fork":           repo.fork,
+				"default_branch": "main",
+				"owner":          map[string]string{"login": username},
+			}
+			if repo.fork {
+				details["parent"] = map[string]interface{}{
+					"name":           repo.name,
+					"full_name":      "upstream/" + repo.name,
+					"default_branch": "main",
+					"owner":          map[string]string{"login": "upstream"},
+				}
+			}
+			writeJSON(w, details)
+			return
+		}
+
+		endpoint := parts[1]
+		switch {
+		case repo.empty && (endpoint == "commits" || strings.HasPrefix(endpoint, "git/trees/")):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message": "Git Repository is empty."}`)
+		case repo.missingTree && strings.HasPrefix(endpoint, "git/trees/"):
+			http.NotFound(w, r)
+		case endpoint == "languages":
+			writeJSON(w, map[string]int{"Go": 1000})
+		case endpoint == "commits" && r.URL.Query().Get("path") != "":
+			var list []map[string]interface{}
+			for i, author := range fakeFileAuthors(username, repo, r.URL.Query().Get("path")) {
+				list = append(list, map[string]interface{}{
+					"sha":    fmt.Sprintf("%s-history-%d", repo.name, i),
+					"author": map[string]string{"login": author},
+				})
+			}
+			writeJSON(w, list)
+		case endpoint == "commits":
+			count := repo.commits
+			if perPage, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && perPage < count {
+				last := (repo.commits + perPage - 1) / perPage
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d&per_page=%d>; rel="last"`, fake.URL, r.URL.Path, last, perPage))
+				count = perPage
+			}
+			var list []map[string]interface{}
+			for i := 0; i < count; i++ {
+				list = append(list, map[string]interface{}{
+					"sha": fmt.Sprintf("%s-%d", repo.name, i),
+					"commit": map[string]interface{}{
+						"message": fmt.Sprintf("commit %d", i),
+						"author":  map[string]interface{}{"name": username, "email": username + "@example.com", "date": updated.Add(-time.Duration(i) * time.Minute)},
+					},
+					"author": map[string]string{"login": username},
+				})
+			}
+			if r.URL.Query().Get("author") == "" {
+				for i, author := range repo.unlinked {
+					name, email, _ := strings.Cut(strings.TrimSuffix(author, ">"), " <")
+					list = append([]map[string]interface{}{{
+						"sha": fmt.Sprintf("%s-unlinked-%d", repo.name, i),
+						"commit": map[string]interface{}{
+							"message": fmt.Sprintf("unlinked commit %d", i),
+							"author":  map[string]interface{}{"name": name, "email": email, "date": updated.Add(time.Duration(i+1) * time.Minute)},
+						},
+					}}, list...)
+				}
+			}
+			writeJSON(w, list)
+		case strings.HasPrefix(endpoint, "commits/"):
+			sha := strings.TrimPrefix(endpoint, "commits/")
+			writeJSON(w, map[string]interface{}{
+				"sha": sha,
+				"files": []map[string]interface{}{{
+					"filename":  sha + ".go",
+					"additions": 2,
+					"patch":     fmt.Sprintf("@@ -0,0 +1,2 @@\n+package main\n+// %s", sha),
+				}},
+			})
+		case strings.HasPrefix(endpoint, "git/trees/"):
+			var entries []map[string]interface{}
+			for _, path := range repo.files {
+				entries = append(entries, map[string]interface{}{"path": path, "type": "blob"})
+			}
+			writeJSON(w, map[string]interface{}{"sha": "HEAD", "tree": entries})
+		case strings.HasPrefix(endpoint, "contents/"):
+			atomic.AddInt32(&fake.contentRequests, 1)
+			path := strings.TrimPrefix(endpoint, "contents/")
+			content, ok := repo.contents[path]
+			if !ok {
+				content = fmt.Sprintf("package %s\n\n// %s\n", repo.name, path)
+			}
+			writeJSON(w, map[string]interface{}{
+				"type":     "file",
+				"path":     path,
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mux.HandleFunc("/repos/upstream/", func(w http.ResponseWriter, r *http.Request) {
+		name, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/repos/upstream/"), "/compare/")
+		repo, ok := byName[name]
+		if !ok || !repo.fork || repo.upstreamGone {
+			http.NotFound(w, r)
+			return
+		}
+
+		var list []map[string]interface{}
+		for i := 0; i < repo.forkCommits; i++ {
+			list = append(list, map[string]interface{}{
+				"sha":    fmt.Sprintf("%s-%d", repo.name, i),
+				"author": map[string]string{"login": username},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"ahead_by": repo.forkCommits, "commits": list})
+	})
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		fake.recordSearch(query)
+		var items []map[string]interface{}
+		if strings.HasPrefix(query, "type:issue") {
+			for _, issue := range fake.issues {
+				if issue.private && strings.Contains(query, "is:public") {
+					continue
+				}
+				commented := false
+				for _, commenter := range issue.commenters {
+					commented = commented || commenter == username
+				}
+				authored := issue.author == username
+				if strings.HasPrefix(issue.repo, username+"/") ||
+					strings.Contains(query, "commenter:") && (!commented || authored) ||
+					!strings.Contains(query, "commenter:") && !authored {
+					continue
+				}
+				var labels []map[string]string
+				for _, label := range issue.labels {
+					labels = append(labels, map[string]string{"name": label})
+				}
+				items = append(items, map[string]interface{}{
+					"number":         issue.number,
+					"title":          issue.title,
+					"state":          "open",
+					"labels":         labels,
+					"comments":       len(issue.commenters),
+					"user":           map[string]string{"login": issue.author},
+					"repository_url": fake.URL + "/repos/" + issue.repo,
+				})
+			}
+			writeJSON(w, map[string]interface{}{"total_count": len(items), "items": items})
+			return
+		}
+		for _, pr := range fake.pullRequests {
+			if pr.private && strings.Contains(query, "is:public") {
+				continue
+			}
+			reviewed := false
+			for _, review := range pr.reviews {
+				reviewed = reviewed || review.user == username
+			}
+			authored := pr.author == username
+			if strings.Contains(query, "reviewed-by:") && (!reviewed || authored) ||
+				!strings.Contains(query, "reviewed-by:") && !authored ||
+				strings.Contains(query, "is:merged") && !pr.merged {
+				continue
+			}
+			items = append(items, map[string]interface{}{
+				"number":         pr.number,
+				"title":          fmt.Sprintf("PR %d", pr.number),
+				"state":          "closed",
+				"user":           map[string]string{"login": pr.author},
+				"repository_url": fake.URL + "/repos/" + pr.repo,
+				"pull_request":   map[string]string{"url": fmt.Sprintf("%s/repos/%s/pulls/%d", fake.URL, pr.repo, pr.number)},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"total_count": len(items), "items": items})
+	})
+	mux.HandleFunc("/search/commits", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		fake.recordSearch(query)
+		var items []map[string]interface{}
+		for _, commit := range fake.externalCommits {
+			if commit.private && strings.Contains(query, "is:public") {
+				continue
+			}
+			owner, name, _ := strings.Cut(commit.repo, "/")
+			items = append(items, map[string]interface{}{
+				"sha": commit.sha,
+				"repository": map[string]interface{}{
+					"name":      name,
+					"full_name": commit.repo,
+					"owner":     map[string]string{"login": owner},
+				},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"total_count": len(items), "items": items})
+	})
+	mux.HandleFunc("/repos/", func(w http.ResponseWriter, r *http.Request) {
+		for repo, stars := range fake.stars {
+			if r.URL.Path == "/repos/"+repo {
+				writeJSON(w, map[string]interface{}{"full_name": repo, "stargazers_count": stars, "language": "Go"})
+				return
+			}
+		}
+		for _, commit := range fake.externalCommits {
+			if r.URL.Path == "/repos/"+commit.repo+"/commits/"+commit.sha {
+				writeJSON(w, map[string]interface{}{
+					"sha": commit.sha,
+					"files": []map[string]interface{}{
+						{"filename": commit.path, "additions": 1, "patch": commit.patch},
+						{"filename": "logo.png"},
+					},
+				})
+				return
+			}
+		}
+		for _, pr := range fake.pullRequests {
+			if pr.missing {
+				continue
+			}
+			prefix := fmt.Sprintf("/repos/%s/pulls/%d", pr.repo, pr.number)
+			switch r.URL.Path {
+			case prefix:
+				writeJSON(w, map[string]interface{}{
+					"number":          pr.number,
+					"merged":          pr.merged,
+					"additions":       pr.additions,
+					"review_comments": len(pr.reviews),
+				})
+				return
+			case prefix + "/reviews":
+				var list []map[string]interface{}
+				for _, review := range pr.reviews {
+					list = append(list, map[string]interface{}{
+						"user":  map[string]string{"login": review.user},
+						"state": review.state,
+						"body":  review.body,
+					})
+				}
+				writeJSON(w, list)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served := atomic.AddInt32(&fake.requests, 1)
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-int(served)))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(updated.Add(48*time.Hour).Unix(), 10))
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// fakeGraphQLData answers the GraphQL queries sent by graphQLCollector with the same data as the REST endpoints
+func fakeGraphQLData(username string, repos []fakeRepo, updated time.Time, query string, variables map[string]interface{}) map[string]interface{} {
+	offset := func(cursor interface{}) int {
+		value, _ := cursor.(string)
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+
+	switch {
+	case strings.HasPrefix(query, "query UserProfile"):
+		return map[string]interface{}{"user": map[string]interface{}{
+			"id":           "user-node-id",
+			"login":        username,
+			"followers":    map[string]int{"totalCount": 0},
+			"following":    map[string]int{"totalCount": 0},
+			"gists":        map[string]int{"totalCount": 0},
+			"repositories": map[string]int{"totalCount": len(repos)},
+		}}
+
+	case strings.HasPrefix(query, "query UserRepositories"):
+		// Keep the original positions so update times match the REST listing
+		var listed []int
+		for i, repo := range repos {
+			// By default, repositories the user collaborates on are listed too
+			if repo.collaborator && fmt.Sprint(variables["affiliations"]) == "[OWNER]" {
+				continue
+			}
+			if !repo.private || variables["privacy"] == nil {
+				listed = append(listed, i)
+			}
+		}
+
+		start := offset(variables["cursor"])
+		end := min(start+fakeRepositoryPageSize, len(listed))
+		var nodes []map[string]interface{}
+		for _, i := range listed[start:end] {
+			node := map[string]interface{}{
+				"name":             repos[i].name,
+				"isFork":           repos[i].fork,
+				"isPrivate":        repos[i].private,
+				"isArchived":       repos[i].archived,
+				"updatedAt":        updated.Add(-time.Duration(i) * time.Hour),
+				"pushedAt":         fakePushedAt(repos[i], i, updated),
+				"repositoryTopics": map[string]interface{}{"nodes": []map[string]interface{}{{"topic": map[string]string{"name": "cli"}}, {"topic": map[string]string{"name": repos[i].name}}}},
+				"licenseInfo":      map[string]string{"spdxId": "MIT"},
+				"diskUsage":        120,
+				"forkCount":        3,
+				"watchers":         map[string]int{"totalCount": 2},
+				"issues":           map[string]int{"totalCount": 3},
+				"pullRequests":     map[string]int{"totalCount": 1},
+				"homepageUrl":      "https://example.com/" + repos[i].name,
+				"defaultBranchRef": map[string]string{"name": "main"},
+				"languages": map[string]interface{}{"edges": []map[string]interface{}{
+					{"size": 1000, "node": map[string]string{"name": "Go"}},
+				}},
+			}
+			if repos[i].fork {
+				node["parent"] = map[string]string{"nameWithOwner": "upstream/" + repos[i].name}
+			}
+			nodes = append(nodes, node)
+		}
+		return map[string]interface{}{"user": map[string]interface{}{"repositories": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": end < len(listed), "endCursor": strconv.Itoa(end)},
+			"nodes":    nodes,
+		}}}
+
+	case strings.HasPrefix(query, "query FileBlame"):
+		var repo fakeRepo
+		for _, candidate := range repos {
+			if candidate.name == variables["name"] {
+				repo = candidate
+			}
+		}
+
+		target := make(map[string]interface{})
+		for i := 0; ; i++ {
+			path, ok := variables[fmt.Sprintf("p%d", i)].(string)
+			if !ok {
+				break
+			}
+			var ranges []map[string]interface{}
+			for line, author := range fakeFileAuthors(username, repo, path) {
+				ranges = append(ranges, map[string]interface{}{
+					"startingLine": line + 1,
+					"endingLine":   line + 1,
+					"commit":       map[string]interface{}{"author": map[string]interface{}{"user": map[string]string{"login": author}}},
+				})
+			}
+			target[fmt.Sprintf("f%d", i)] = map[string]interface{}{"ranges": ranges}
+		}
+		return map[string]interface{}{"repository": map[string]interface{}{
+			"defaultBranchRef": map[string]interface{}{"target": target},
+		}}
+
+	case strings.HasPrefix(query, "query RepositoryCommits"):
+		data := make(map[string]interface{})
+		for i := 0; ; i++ {
+			name, ok := variables[fmt.Sprintf("r%d", i)].(string)
+			if !ok {
+				break
+			}
+			var repo fakeRepo
+			for _, candidate := range repos {
+				if candidate.name == name {
+					repo = candidate
+				}
+			}
+
+			start := offset(variables[fmt.Sprintf("c%d", i)])
+			limit := int(variables[fmt.Sprintf("n%d", i)].(float64))
+			end := min(start+min(limit, fakeCommitPageSize), repo.commits)
+			var nodes []map[string]interface{}
+			for c := start; c < end; c++ {
+				nodes = append(nodes, map[string]interface{}{
+					"oid":          fmt.Sprintf("%s-%d", repo.name, c),
+					"message":      fmt.Sprintf("commit %d", c),
+					"authoredDate": updated.Add(-time.Duration(c) * time.Minute),
+					"author":       map[string]string{"name": username, "email": username + "@example.com"},
+				})
+			}
+			if repo.empty {
+				data[fmt.Sprintf("r%d", i)] = map[string]interface{}{"defaultBranchRef": nil}
+				continue
+			}
+			data[fmt.Sprintf("r%d", i)] = map[string]interface{}{"defaultBranchRef": map[string]interface{}{
+				"target": map[string]interface{}{"history": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": end < repo.commits, "endCursor": strconv.Itoa(end)},
+					"nodes":    nodes,
+				}},
+			}}
+		}
+		return data
+	}
+
+	return nil
+}
+
+// newFakeGitHubService creates a service that talks to the fake GitHub API server
+func newFakeGitHubService(t *testing.T, server *fakeGitHubServer, cfg *config.GitHubConfig) *GitHubService {
+	t.Helper()
+
+	service := NewGitHubService(cfg)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+	service.client = github.NewClient(server.Client())
+	service.client.BaseURL = baseURL
+	return service
+}
+
+// useTransport routes the service's GitHub API calls through the given transport
+func useTransport(service *GitHubService, transport http.RoundTripper) {
+	baseURL := service.client.BaseURL
+	service.client = github.NewClient(&http.Client{Transport: transport})
+	service.client.BaseURL = baseURL
+}
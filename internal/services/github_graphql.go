@@ -0,0 +1,533 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Repositories whose commit history is requested in a single GraphQL query
+const graphQLCommitBatchSize = 10
+
+// Maximum page size accepted by the GraphQL API
+const graphQLPageSize = 100
+
+const userProfileQuery = `query UserProfile($login: String!) {
+  user(login: $login) {
+    id login name company location email createdAt updatedAt
+    followers { totalCount }
+    following { totalCount }
+    gists(privacy: PUBLIC) { totalCount }
+    repositories(privacy: PUBLIC, ownerAffiliations: [OWNER]) { totalCount }
+  }
+}`
+
+const userRepositoriesQuery = `query UserRepositories($login: String!, $cursor: String, $privacy: RepositoryPrivacy, $affiliations: [RepositoryAffiliation] = [OWNER, COLLABORATOR]) {
+  user(login: $login) {
+    repositories(first: 100, after: $cursor, privacy: $privacy, ownerAffiliations: $affiliations, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name description createdAt updatedAt pushedAt stargazerCount forkCount diskUsage homepageUrl
+        isFork isPrivate isArchived isDisabled
+        watchers { totalCount }
+        issues(states: OPEN) { totalCount }
+        pullRequests(states: OPEN) { totalCount }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        licenseInfo { spdxId }
+        defaultBranchRef { name }
+        parent { nameWithOwner }
+        languages(first: 20, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name } } }
+      }
+    }
+  }
+}`
+
+// Commit history of one repository, repeated under an alias for every repository in a batch
+const repositoryCommitsFragment = `  r%[1]d: repository(owner: $owner, name: $r%[1]d) {
+    defaultBranchRef {
+      target {
+        ... on Commit {
+          history(first: $n%[1]d, after: $c%[1]d, author: {id: $author}) {
+            pageInfo { hasNextPage endCursor }
+            nodes { oid message authoredDate author { name email } }
+          }
+        }
+      }
+    }
+  }
+`
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type graphQLUser struct {
+	ID           string       `json:"id"`
+	Login        string       `json:"login"`
+	Name         string       `json:"name"`
+	Company      string       `json:"company"`
+	Location     string       `json:"location"`
+	Email        string       `json:"email"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+	Followers    graphQLCount `json:"followers"`
+	Following    graphQLCount `json:"following"`
+	Gists        graphQLCount `json:"gists"`
+	Repositories graphQLCount `json:"repositories"`
+}
+
+type graphQLRepository struct {
+	Name             string       `json:"name"`
+	Description      string       `json:"description"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+	PushedAt         time.Time    `json:"pushedAt"`
+	StargazerCount   int          `json:"stargazerCount"`
+	ForkCount        int          `json:"forkCount"`
+	DiskUsage        int          `json:"diskUsage"` // Kilobytes
+	HomepageURL      string       `json:"homepageUrl"`
+	IsFork           bool         `json:"isFork"`
+	IsPrivate        bool         `json:"isPrivate"`
+	IsArchived       bool         `json:"isArchived"`
+	IsDisabled       bool         `json:"isDisabled"`
+	Watchers         graphQLCount `json:"watchers"`
+	Issues           graphQLCount `json:"issues"`
+	PullRequests     graphQLCount `json:"pullRequests"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	LicenseInfo *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	Parent *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"parent"`
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+}
+
+type graphQLCommitHistory struct {
+	DefaultBranchRef *struct {
+		Target struct {
+			History struct {
+				PageInfo graphQLPageInfo `json:"pageInfo"`
+				Nodes    []struct {
+					OID          string    `json:"oid"`
+					Message      string    `json:"message"`
+					AuthoredDate time.Time `json:"authoredDate"`
+					Author       struct {
+						Name  string `json:"name"`
+						Email string `json:"email"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// commitHistory holds the prefetched commits of a repository
+type commitHistory struct {
+	commits []*dto.CommitDetail
+	err     error
+}
+
+// graphQLCollector collects profile, repository and commit data with batched GraphQL v4 queries.
+// The GraphQL API does not expose the files changed by a commit, so those are still
+// fetched per commit with the REST API.
+type graphQLCollector struct {
+	service *GitHubService
+
+	mu        sync.Mutex
+	userIDs   map[string]string         // Node IDs by login, used to filter commits by author
+	languages map[string][]string       // Languages by "owner/repo", largest first
+	histories map[string]*commitHistory // Prefetched commits by "owner/repo"
+}
+
+// newGraphQLCollector creates a collector that sends GraphQL queries through the service's client
+func newGraphQLCollector(service *GitHubService) *graphQLCollector {
+	return &graphQLCollector{
+		service:   service,
+		userIDs:   make(map[string]string),
+		languages: make(map[string][]string),
+		histories: make(map[string]*commitHistory),
+	}
+}
+
+// reset forgets the user IDs, languages and commit histories kept from a previous audit
+func (c *graphQLCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userIDs = make(map[string]string)
+	c.languages = make(map[string][]string)
+	c.histories = make(map[string]*commitHistory)
+}
+
+// graphQL runs a GraphQL query and decodes its data into result.
+// Errors reported for individual aliases are returned separately so the rest of the batch can be used.
+func (s *GitHubService) graphQL(ctx context.Context, repo, description, query string, variables map[string]interface{}, result interface{}) ([]graphQLError, error) {
+	if s.config.Token == "" {
+		return nil, fmt.Errorf("the GraphQL API requires a GitHub token")
+	}
+
+	var response struct {
+		Data   interface{}    `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	response.Data = result
+
+	err := s.callWithRetry(ctx, repo, description, func() (*github.Response, error) {
+		req, err := s.client.NewRequest("POST", "graphql", &graphQLRequest{Query: query, Variables: variables})
+		if err != nil {
+			return nil, err
+		}
+		response.Errors = nil
+		resp, err := s.client.Do(ctx, req, &response)
+		if err == nil && isGraphQLRateLimited(response.Errors) {
+			// GraphQL reports an exhausted quota in the body of a successful response
+			return resp, &github.RateLimitError{Rate: resp.Rate, Response: resp.Response, Message: "GraphQL API rate limit exceeded"}
+		}
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pathErrors []graphQLError
+	for _, gqlErr := range response.Errors {
+		if len(gqlErr.Path) == 0 {
+			return nil, fmt.Errorf("GraphQL query failed: %s", gqlErr.Message)
+		}
+		pathErrors = append(pathErrors, gqlErr)
+	}
+	return pathErrors, nil
+}
+
+// GetUser retrieves GitHub user information. The GraphQL API doesn't expose the subscription
+// plan, so SubscriptionPlan is left empty.
+func (c *graphQLCollector) GetUser(ctx context.Context, username string) (*dto.UserInfo, error) {
+	var data struct {
+		User *graphQLUser `json:"user"`
+	}
+	_, err := c.service.graphQL(ctx, "", "user lookup", userProfileQuery, map[string]interface{}{"login": username}, &data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", username, err)
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("failed to get user %s: user not found", username)
+	}
+
+	user := data.User
+	c.mu.Lock()
+	c.userIDs[strings.ToLower(username)] = user.ID
+	c.mu.Unlock()
+
+	return &dto.UserInfo{
+		Username:    user.Login,
+		Name:        user.Name,
+		Company:     user.Company,
+		Location:    user.Location,
+		Email:       user.Email,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		PublicRepos: user.Repositories.TotalCount,
+		PublicGists: user.Gists.TotalCount,
+		Followers:   user.Followers.TotalCount,
+		Following:   user.Following.TotalCount,
+	}, nil
+}
+
+// ListRepositories retrieves user repositories together with their languages
+func (c *graphQLCollector) ListRepositories(ctx context.Context, username string) ([]*dto.Repository, error) {
+	// A null privacy filter lists private repositories too
+	var privacy interface{} = "PUBLIC"
+	if c.service.includePrivateRepos(ctx, username) {
+		privacy = nil
+	}
+
+	var repositories []*dto.Repository
+	var cursor interface{}
+	for {
+		var data struct {
+			User *struct {
+				Repositories struct {
+					PageInfo graphQLPageInfo     `json:"pageInfo"`
+					Nodes    []graphQLRepository `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+		variables := map[string]interface{}{"login": username, "cursor": cursor, "privacy": privacy}
+		if c.service.config.OwnedReposOnly {
+			variables["affiliations"] = []string{"OWNER"}
+		}
+		if _, err := c.service.graphQL(ctx, "", "repository listing", userRepositoriesQuery, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		if data.User == nil {
+			return nil, fmt.Errorf("failed to list repositories: user %s not found", username)
+		}
+
+		for _, repo := range data.User.Repositories.Nodes {
+			repository := &dto.Repository{
+				Name:        repo.Name,
+				Description: repo.Description,
+				CreatedAt:   repo.CreatedAt,
+				UpdatedAt:   repo.UpdatedAt,
+				PushedAt:    repo.PushedAt,
+				Stars:       repo.StargazerCount,
+				Watchers:    repo.Watchers.TotalCount,
+				ForksCount:  repo.ForkCount,
+				OpenIssues:  repo.Issues.TotalCount + repo.PullRequests.TotalCount,
+				SizeKB:      repo.DiskUsage,
+				Homepage:    repo.HomepageURL,
+				Fork:        repo.IsFork,
+				Private:     repo.IsPrivate,
+				Archived:    repo.IsArchived,
+				Disabled:    repo.IsDisabled,
+			}
+			if repo.IsFork && repo.Parent != nil {
+				repository.ForkSource = repo.Parent.NameWithOwner
+			}
+			for _, node := range repo.RepositoryTopics.Nodes {
+				repository.Topics = append(repository.Topics, node.Topic.Name)
+			}
+			if repo.LicenseInfo != nil {
+				repository.License = repo.LicenseInfo.SPDXID
+			}
+			if repo.DefaultBranchRef != nil {
+				repository.DefaultBranch = repo.DefaultBranchRef.Name
+			}
+
+			var languages []string
+			for _, edge := range repo.Languages.Edges {
+				languages = append(languages, edge.Node.Name)
+			}
+			c.mu.Lock()
+			c.languages[repoKey(username, repo.Name)] = languages
+			c.mu.Unlock()
+
+			repositories = append(repositories, repository)
+		}
+
+		pageInfo := data.User.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		cursor = pageInfo.EndCursor
+	}
+
+	return repositories, nil
+}
+
+// GetRepositoryLanguages returns the languages received with the repository listing,
+// falling back to the REST API for repositories that were not listed
+func (c *graphQLCollector) GetRepositoryLanguages(ctx context.Context, username, repoName string) ([]string, error) {
+	c.mu.Lock()
+	languages, ok := c.languages[repoKey(username, repoName)]
+	c.mu.Unlock()
+
+	if !ok {
+		return c.service.GetRepositoryLanguages(ctx, username, repoName)
+	}
+	return languages, nil
+}
+
+// GetRepositoryCommits returns the prefetched commits of a repository with their changed files
+func (c *graphQLCollector) GetRepositoryCommits(ctx context.Context, username, repoName string, count int) ([]*dto.CommitDetail, error) {
+	c.mu.Lock()
+	history, ok := c.histories[repoKey(username, repoName)]
+	c.mu.Unlock()
+
+	if !ok {
+		if err := c.prefetchCommits(ctx, username, []*dto.Repository{{Name: repoName}}, count); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		history = c.histories[repoKey(username, repoName)]
+		c.mu.Unlock()
+	}
+
+	if history.err != nil {
+		return nil, fmt.Errorf("failed to get commits for %s/%s: %w", username, repoName, history.err)
+	}
+
+	// Changed files are added to copies, so the prefetched history stays as GitHub returned it
+	commits := make([]*dto.CommitDetail, min(len(history.commits), count))
+	for i := range commits {
+		commit := *history.commits[i]
+		commits[i] = &commit
+	}
+	if err := c.service.fetchChangedFiles(ctx, username, repoName, commits); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// prefetchCommits fetches the commits authored by the user in the given repositories,
+// requesting the history of several repositories per query
+func (c *graphQLCollector) prefetchCommits(ctx context.Context, username string, repos []*dto.Repository, count int) error {
+	c.mu.Lock()
+	authorID, ok := c.userIDs[strings.ToLower(username)]
+	c.mu.Unlock()
+	if !ok {
+		if _, err := c.GetUser(ctx, username); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for _, repo := range repos {
+				c.storeHistory(username, repo.Name, &commitHistory{err: err})
+			}
+			return nil
+		}
+		c.mu.Lock()
+		authorID = c.userIDs[strings.ToLower(username)]
+		c.mu.Unlock()
+	}
+
+	// Repositories still being paginated, with the cursor of their next page
+	type pending struct {
+		name    string
+		cursor  interface{}
+		history *commitHistory
+	}
+	var queue []*pending
+	for _, repo := range repos {
+		queue = append(queue, &pending{name: repo.Name, history: &commitHistory{}})
+	}
+
+	for len(queue) > 0 {
+		batch := queue
+		if len(batch) > graphQLCommitBatchSize {
+			batch = batch[:graphQLCommitBatchSize]
+		}
+		queue = queue[len(batch):]
+
+		var query strings.Builder
+		query.WriteString("query RepositoryCommits($owner: String!, $author: ID!")
+		variables := map[string]interface{}{"owner": username, "author": authorID}
+		for i, p := range batch {
+			fmt.Fprintf(&query, ", $r%[1]d: String!, $n%[1]d: Int!, $c%[1]d: String", i)
+			variables[fmt.Sprintf("r%d", i)] = p.name
+			variables[fmt.Sprintf("n%d", i)] = min(count-len(p.history.commits), graphQLPageSize)
+			variables[fmt.Sprintf("c%d", i)] = p.cursor
+		}
+		query.WriteString(") {\n")
+		for i := range batch {
+			fmt.Fprintf(&query, repositoryCommitsFragment, i)
+		}
+		query.WriteString("}")
+
+		s := c.service
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressRepositories,
+			Message: fmt.Sprintf("Fetching commit history for %d repositories...", len(batch)),
+		})
+
+		data := make(map[string]*graphQLCommitHistory, len(batch))
+		pathErrors, err := c.service.graphQL(ctx, "", "commit history", query.String(), variables, &data)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		for i, p := range batch {
+			alias := fmt.Sprintf("r%d", i)
+			switch {
+			case err != nil:
+				p.history.err = err
+			case aliasError(pathErrors, alias) != nil:
+				p.history.err = aliasError(pathErrors, alias)
+			case data[alias] == nil:
+				p.history.err = fmt.Errorf("repository not found")
+			case data[alias].DefaultBranchRef == nil:
+				p.history.err = errEmptyRepository
+			default:
+				history := data[alias].DefaultBranchRef.Target.History
+				for _, node := range history.Nodes {
+					p.history.commits = append(p.history.commits, &dto.CommitDetail{
+						Repo:        p.name,
+						SHA:         node.OID,
+						Message:     node.Message,
+						Author:      node.Author.Name,
+						AuthorEmail: node.Author.Email,
+						MatchedBy:   dto.MatchedByLogin,
+						Date:        node.AuthoredDate,
+					})
+				}
+				if history.PageInfo.HasNextPage && len(p.history.commits) < count {
+					p.cursor = history.PageInfo.EndCursor
+					queue = append(queue, p)
+					continue
+				}
+			}
+			c.storeHistory(username, p.name, p.history)
+		}
+	}
+
+	return nil
+}
+
+// storeHistory records the prefetched commits of a repository
+func (c *graphQLCollector) storeHistory(username, repoName string, history *commitHistory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.histories[repoKey(username, repoName)] = history
+}
+
+// isGraphQLRateLimited reports whether the query was rejected by the GraphQL rate limit
+func isGraphQLRateLimited(errs []graphQLError) bool {
+	for _, gqlErr := range errs {
+		if gqlErr.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasError returns the first error reported for a query alias
+func aliasError(errs []graphQLError, alias string) error {
+	for _, gqlErr := range errs {
+		if name, ok := gqlErr.Path[0].(string); ok && name == alias {
+			return fmt.Errorf("%s", gqlErr.Message)
+		}
+	}
+	return nil
+}
+
+// repoKey identifies a repository in the collector caches
+func repoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
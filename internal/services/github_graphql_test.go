@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestGraphQLCollectorMatchesREST(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "alpha", commits: 7, files: []string{"a.go", "b.go", "c.go"}},
+		{name: "beta", commits: 2, files: []string{"main.go"}},
+		{name: "gamma", commits: 0, files: []string{"x.go"}},
+		{name: "delta", fork: true, commits: 4, files: []string{"p.py", "q.py"}},
+		{name: "epsilon", commits: 1, files: []string{"e.rs"}, archived: true},
+	}
+	server := newFakeGitHubServer(t, "octocat", repos)
+
+	// The GraphQL API doesn't expose the subscription plan
+	expectedPlans := map[string]string{config.CollectorREST: "pro", config.CollectorGraphQL: ""}
+
+	audit := func(backend string) string {
+		cfg := config.DefaultGitHubConfig()
+		cfg.Token = "test-token"
+		cfg.CommitsPerRepo = 5
+		cfg.SampleFileCount = 2
+		cfg.CollectorBackend = backend
+		cfg.EstimateOwnership = false // Blame queries are sent with either backend
+
+		result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+		if err != nil {
+			t.Fatalf("PerformFullAudit() with %s backend failed: %v", backend, err)
+		}
+
+		if plan := result.UserInfo.SubscriptionPlan; plan != expectedPlans[backend] {
+			t.Errorf("SubscriptionPlan with %s backend = %q, expected %q", backend, plan, expectedPlans[backend])
+		}
+		result.UserInfo.SubscriptionPlan = ""
+
+		// Compare the collected data only, parameters and API usage differ by design
+		data, err := json.Marshal([]interface{}{result.UserInfo, result.RepoStats, result.CommitDetails, result.FileAnalysis})
+		if err != nil {
+			t.Fatalf("Failed to marshal result: %v", err)
+		}
+		return string(data)
+	}
+
+	rest := audit(config.CollectorREST)
+	queriesBefore := server.graphQLQueries
+	graphQL := audit(config.CollectorGraphQL)
+
+	if graphQL != rest {
+		t.Errorf("GraphQL backend produced different data.\nREST:    %s\nGraphQL: %s", rest, graphQL)
+	}
+	if queriesBefore != 0 {
+		t.Errorf("REST backend should not send GraphQL queries, got %d", queriesBefore)
+	}
+
+	// 1 profile query, 3 repository pages and 2 rounds of batched commit history (alpha needs a second page)
+	if queries := server.graphQLQueries; queries != 6 {
+		t.Errorf("Expected 6 GraphQL queries, got %d", queries)
+	}
+}
+
+func TestGraphQLCollectorRequiresToken(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", nil)
+	cfg := config.DefaultGitHubConfig()
+	cfg.CollectorBackend = config.CollectorGraphQL
+
+	_, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err == nil {
+		t.Fatal("Expected an error without a GitHub token")
+	}
+	if server.graphQLQueries != 0 {
+		t.Errorf("No GraphQL query should be sent without a token, got %d", server.graphQLQueries)
+	}
+}
+
+func TestAliasError(t *testing.T) {
+	errs := []graphQLError{
+		{Message: "Could not resolve to a Repository", Path: []interface{}{"r1"}},
+	}
+
+	if err := aliasError(errs, "r0"); err != nil {
+		t.Errorf("Expected no error for r0, got %v", err)
+	}
+	if err := aliasError(errs, "r1"); err == nil {
+		t.Error("Expected an error for r1")
+	}
+}
+
+func TestGraphQLCollectorStartsEachAuditAfresh(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go"}},
+		{name: "beta", commits: 1, files: []string{"b.go"}},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.Token = "test-token"
+	cfg.CollectorBackend = config.CollectorGraphQL
+	service := newFakeGitHubService(t, server, cfg)
+
+	// Commits handed out twice get their changed files once each time
+	for i := 0; i < 2; i++ {
+		commits, err := service.collector.GetRepositoryCommits(context.Background(), "octocat", "alpha", 5)
+		if err != nil {
+			t.Fatalf("GetRepositoryCommits() failed: %v", err)
+		}
+		if len(commits) != 2 || commits[0].Additions != 2 || len(commits[0].FilesChanged) != 1 {
+			t.Errorf("Call %d: unexpected commits %+v", i+1, commits)
+		}
+	}
+
+	// A later audit doesn't see what an earlier one collected
+	other := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "beta", commits: 3, files: []string{"b.go"}}})
+	service.client = newFakeGitHubService(t, other, cfg).client
+	if _, err := service.PerformFullAudit(context.Background(), "octocat"); err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+	collector := service.collector.(*graphQLCollector)
+	if _, ok := collector.histories[repoKey("octocat", "alpha")]; ok {
+		t.Error("Commit history of alpha was kept from before the audit")
+	}
+	if history := collector.histories[repoKey("octocat", "beta")]; history == nil || len(history.commits) != 3 {
+		t.Errorf("Expected the 3 commits of beta, got %+v", history)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Recent commits of a repository searched for commits made with the user's other identities
+const identityScanCount = 100
+
+// Domain of the private commit emails GitHub assigns, login@ or id+login@
+const noreplyDomain = "@users.noreply.github.com"
+
+// authorIdentities holds the lower-cased git author emails and names attributed to the user
+type authorIdentities struct {
+	login  string
+	emails map[string]bool
+	names  map[string]bool
+}
+
+// userIdentities collects the user's git author emails: the configured email aliases and the
+// emails of the commits GitHub linked to the user, across all analyzed repositories. Names are
+// too common to identify the user alone, so a name is only used when configured as an alias
+// and seen on a commit linked to the user, next to an email known to be theirs.
+func (s *GitHubService) userIdentities(username string, commits []*dto.CommitDetail) authorIdentities {
+	ids := authorIdentities{login: strings.ToLower(username), emails: make(map[string]bool), names: make(map[string]bool)}
+	linkedNames := make(map[string]bool)
+	for _, commit := range commits {
+		if commit.MatchedBy != dto.MatchedByLogin {
+			continue
+		}
+		if email := strings.ToLower(strings.TrimSpace(commit.AuthorEmail)); email != "" {
+			ids.emails[email] = true
+		}
+		linkedNames[strings.ToLower(strings.TrimSpace(commit.Author))] = true
+	}
+
+	for _, alias := range s.config.AuthorAliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		switch {
+		case alias == "":
+		case strings.Contains(alias, "@"):
+			ids.emails[alias] = true
+		case linkedNames[alias]:
+			ids.names[alias] = true
+		}
+	}
+	return ids
+}
+
+// isEmpty reports whether no commit can match the identities
+func (ids authorIdentities) isEmpty() bool {
+	return ids.login == "" && len(ids.emails) == 0 && len(ids.names) == 0
+}
+
+// match reports how a git author matches the identities, preferring the email, and the
+// identity that matched
+func (ids authorIdentities) match(name, email string) (dto.CommitMatch, string) {
+	if email != "" && (ids.emails[strings.ToLower(email)] || ids.isNoreply(email)) {
+		return dto.MatchedByEmail, email
+	}
+	if name != "" && ids.names[strings.ToLower(name)] {
+		return dto.MatchedByName, name
+	}
+	return "", ""
+}
+
+// isNoreply reports whether an email is one of the user's private GitHub commit emails
+func (ids authorIdentities) isNoreply(email string) bool {
+	local, found := strings.CutSuffix(strings.ToLower(email), noreplyDomain)
+	if !found || ids.login == "" {
+		return false
+	}
+	return local == ids.login || strings.HasSuffix(local, "+"+ids.login)
+}
+
+// addIdentityCommits adds the repository's recent commits made with the user's identities to
+// the commits GitHub linked to the user, keeping the most recent count commits. Only commits not
+// linked to any account are attributed this way; commits linked to another account are never the
+// user's, and the user's own are already listed. The commits are returned unchanged if the
+// repository can't be searched.
+func (s *GitHubService) addIdentityCommits(ctx context.Context, username, repoName string, commits []*dto.CommitDetail, count int, ids authorIdentities) []*dto.CommitDetail {
+	if ids.isEmpty() {
+		return commits
+	}
+
+	opt := &github.CommitsListOptions{ListOptions: github.ListOptions{PerPage: identityScanCount}}
+	var recent []*github.RepositoryCommit
+	err := s.callWithRetry(ctx, repoName, "commit listing", func() (resp *github.Response, err error) {
+		recent, resp, err = s.client.Repositories.ListCommits(ctx, username, repoName, opt)
+		return resp, err
+	})
+	if err != nil {
+		return commits
+	}
+
+	known := make(map[string]bool, len(commits))
+	for _, commit := range commits {
+		known[commit.SHA] = true
+	}
+
+	var found []*dto.CommitDetail
+	for _, commit := range recent {
+		author := commit.GetCommit().GetAuthor()
+		if known[commit.GetSHA()] || commit.GetAuthor().GetLogin() != "" {
+			continue
+		}
+		matchedBy, identity := ids.match(author.GetName(), author.GetEmail())
+		if matchedBy == "" {
+			continue
+		}
+
+		detail := &dto.CommitDetail{
+			Repo:            repoName,
+			SHA:             commit.GetSHA(),
+			Message:         commit.GetCommit().GetMessage(),
+			Author:          author.GetName(),
+			AuthorEmail:     author.GetEmail(),
+			MatchedBy:       matchedBy,
+			MatchedIdentity: identity,
+		}
+		if author.Date != nil {
+			detail.Date = author.Date.Time
+		}
+		found = append(found, detail)
+	}
+	if len(found) == 0 {
+		return commits
+	}
+
+	// Keep the most recent commits of both kinds
+	merged := append(append([]*dto.CommitDetail(nil), commits...), found...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Date.After(merged[j].Date)
+	})
+	merged = merged[:min(len(merged), count)]
+
+	var added []*dto.CommitDetail
+	for _, commit := range merged {
+		if commit.MatchedBy != dto.MatchedByLogin {
+			added = append(added, commit)
+		}
+	}
+	if err := s.fetchChangedFiles(ctx, username, repoName, added); err != nil {
+		return commits
+	}
+	return merged
+}
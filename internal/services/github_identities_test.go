@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestGetRepositoryCommitsMatchesIdentities(t *testing.T) {
+	repos := []fakeRepo{{
+		name:    "alpha",
+		commits: 2,
+		files:   []string{"a.go"},
+		unlinked: []string{
+			"Octo Cat <octo@personal.example>",
+			"octocat <octo@work.example>",
+			"Someone Else <else@example.com>",
+			"Octo <12345+octocat@users.noreply.github.com>",
+		},
+	}}
+
+	// The newest unlinked commit uses the user's private GitHub email and always matches
+	testCases := []struct {
+		name     string
+		aliases  []string
+		expected string
+	}{
+		{
+			"names seen on linked commits alone",
+			nil,
+			"alpha-unlinked-3 email:12345+octocat@users.noreply.github.com, alpha-0 login:, alpha-1 login:",
+		},
+		{
+			"configured email alias",
+			[]string{"OCTO@personal.example"},
+			"alpha-unlinked-3 email:12345+octocat@users.noreply.github.com, alpha-unlinked-0 email:octo@personal.example, alpha-0 login:",
+		},
+		{
+			"configured name seen on linked commits",
+			[]string{"octocat"},
+			"alpha-unlinked-3 email:12345+octocat@users.noreply.github.com, alpha-unlinked-1 name:octocat, alpha-0 login:",
+		},
+		{
+			"configured name never seen on linked commits",
+			[]string{"Someone Else"},
+			"alpha-unlinked-3 email:12345+octocat@users.noreply.github.com, alpha-0 login:, alpha-1 login:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeGitHubServer(t, "octocat", repos)
+			cfg := config.DefaultGitHubConfig()
+			cfg.CommitsPerRepo = 3
+			cfg.AuthorAliases = tc.aliases
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			var matches []string
+			for _, commit := range result.CommitDetails {
+				matches = append(matches, fmt.Sprintf("%s %s:%s", commit.SHA, commit.MatchedBy, commit.MatchedIdentity))
+				if commit.Additions != 2 {
+					t.Errorf("Changed lines of %s were not fetched", commit.SHA)
+				}
+			}
+			if got := strings.Join(matches, ", "); got != tc.expected {
+				t.Errorf("Commits = %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPerformFullAuditMatchesIdentitiesAcrossRepositories(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go"}},
+		{name: "beta", files: []string{"b.go"}, unlinked: []string{"Octo <octocat@example.com>", "octocat <octo@work.example>"}},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.CommitsPerRepo = 3
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	// beta has no commits linked to the user; the email comes from those in alpha, while a commit
+	// with only the user's name isn't attributed
+	var matches []string
+	for _, commit := range result.CommitDetails {
+		matches = append(matches, fmt.Sprintf("%s %s:%s", commit.SHA, commit.MatchedBy, commit.MatchedIdentity))
+	}
+	expected := "alpha-0 login:, alpha-1 login:, beta-unlinked-0 email:octocat@example.com"
+	if got := strings.Join(matches, ", "); got != expected {
+		t.Errorf("Commits = %s, expected %s", got, expected)
+	}
+}
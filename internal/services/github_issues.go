@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Issue participation roles
+const (
+	issueRoleAuthor    = "author"
+	issueRoleCommenter = "commenter"
+)
+
+// Label and title keywords used to classify issues opened by the user
+var (
+	bugKeywords      = []string{"bug", "defect", "crash", "regression", "error", "fail", "broken", "panic", "exception", "not working", "doesn't work"}
+	questionKeywords = []string{"question", "how to", "how do", "how can", "is it possible", "support request", "usage"}
+	featureKeywords  = []string{"feature", "enhancement", "proposal", "request", "add support", "support for", "allow", "would be nice"}
+)
+
+// CollectIssueActivity returns the issues the user opened or commented on in repositories owned by others,
+// classified by the kind of participation and summarized per repository
+func (s *GitHubService) CollectIssueActivity(ctx context.Context, username string, count int) (*dto.IssueActivity, error) {
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressActivity,
+		Message:  fmt.Sprintf("Searching issues opened or commented on by %s...", username),
+		Fraction: activityProgress,
+	})
+
+	// -user excludes the user's own repositories, where issues are mostly maintenance, and is:public
+	// the private repositories the token can see
+	opened, err := s.searchIssues(ctx, fmt.Sprintf("type:issue author:%s -user:%s is:public", username, username), count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues opened by %s: %w", username, err)
+	}
+
+	activity := &dto.IssueActivity{}
+	for _, issue := range opened {
+		activity.Issues = append(activity.Issues, newIssue(issue, issueRoleAuthor, classifyIssue(issueLabels(issue), issue.GetTitle())))
+	}
+
+	commented, err := s.searchIssues(ctx, fmt.Sprintf("type:issue commenter:%s -author:%s -user:%s is:public", username, username, username), count)
+	if err != nil {
+		err = fmt.Errorf("failed to search issues commented on by %s: %w", username, err)
+	}
+	for _, issue := range commented {
+		activity.Issues = append(activity.Issues, newIssue(issue, issueRoleCommenter, dto.IssueSupportAnswer))
+	}
+
+	activity.Repositories = summarizeIssues(activity.Issues)
+	return activity, err
+}
+
+// newIssue converts a search result into the audit representation
+func newIssue(issue *github.Issue, role string, category dto.IssueCategory) *dto.Issue {
+	owner, repo := issueRepository(issue)
+	return &dto.Issue{
+		Repo:      owner + "/" + repo,
+		Number:    issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		State:     issue.GetState(),
+		Labels:    issueLabels(issue),
+		Author:    issue.GetUser().GetLogin(),
+		Role:      role,
+		Category:  category,
+		Comments:  issue.GetComments(),
+		CreatedAt: issue.GetCreatedAt().Time,
+		URL:       issue.GetHTMLURL(),
+	}
+}
+
+// issueLabels returns the names of the labels attached to an issue
+func issueLabels(issue *github.Issue) []string {
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.GetName())
+	}
+	return labels
+}
+
+// classifyIssue classifies an issue opened by the user from its labels, falling back to its title
+func classifyIssue(labels []string, title string) dto.IssueCategory {
+	for _, label := range labels {
+		label = strings.ToLower(label)
+		switch {
+		case containsAny(label, bugKeywords):
+			return dto.IssueBugReport
+		case containsAny(label, questionKeywords) || label == "support" || label == "help":
+			return dto.IssueQuestion
+		case containsAny(label, featureKeywords):
+			return dto.IssueFeatureRequest
+		}
+	}
+
+	title = strings.ToLower(title)
+	switch {
+	case containsAny(title, bugKeywords):
+		return dto.IssueBugReport
+	case containsAny(title, questionKeywords):
+		return dto.IssueQuestion
+	case containsAny(title, featureKeywords):
+		return dto.IssueFeatureRequest
+	case strings.HasSuffix(strings.TrimSpace(title), "?"):
+		return dto.IssueQuestion
+	}
+	return dto.IssueOther
+}
+
+// containsAny reports whether text contains any of the keywords
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeIssues counts the user's participation per repository, most active repositories first
+func summarizeIssues(issues []*dto.Issue) []*dto.IssueRepoSummary {
+	byRepo := make(map[string]*dto.IssueRepoSummary)
+	var summaries []*dto.IssueRepoSummary
+	for _, issue := range issues {
+		summary, ok := byRepo[issue.Repo]
+		if !ok {
+			summary = &dto.IssueRepoSummary{Repo: issue.Repo}
+			byRepo[issue.Repo] = summary
+			summaries = append(summaries, summary)
+		}
+
+		if issue.Role == issueRoleAuthor {
+			summary.Opened++
+		} else {
+			summary.CommentedOn++
+		}
+		switch issue.Category {
+		case dto.IssueBugReport:
+			summary.BugReports++
+		case dto.IssueFeatureRequest:
+			summary.FeatureRequests++
+		case dto.IssueQuestion:
+			summary.Questions++
+		case dto.IssueSupportAnswer:
+			summary.SupportAnswers++
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		ti := summaries[i].Opened + summaries[i].CommentedOn
+		tj := summaries[j].Opened + summaries[j].CommentedOn
+		if ti != tj {
+			return ti > tj
+		}
+		return summaries[i].Repo < summaries[j].Repo
+	})
+	return summaries
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
+)
+
+func TestClassifyIssue(t *testing.T) {
+	testCases := []struct {
+		labels   []string
+		title    string
+		expected dto.IssueCategory
+	}{
+		{[]string{"bug"}, "Something is odd", dto.IssueBugReport},
+		{[]string{"Type: Enhancement"}, "Improve the docs", dto.IssueFeatureRequest},
+		{[]string{"question"}, "Add support for YAML", dto.IssueQuestion},
+		{[]string{"good first issue", "kind/feature"}, "Config loader", dto.IssueFeatureRequest},
+		{nil, "Crash when the config file is empty", dto.IssueBugReport},
+		{nil, "Add support for YAML configs", dto.IssueFeatureRequest},
+		{nil, "How to configure a proxy", dto.IssueQuestion},
+		{nil, "Is streaming planned?", dto.IssueQuestion},
+		{nil, "Update README", dto.IssueOther},
+	}
+
+	for _, tc := range testCases {
+		if got := classifyIssue(tc.labels, tc.title); got != tc.expected {
+			t.Errorf("classifyIssue(%v, %q) = %s, expected %s", tc.labels, tc.title, got, tc.expected)
+		}
+	}
+}
+
+func TestPerformFullAuditCollectsIssueActivity(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1}})
+	server.issues = []fakeIssue{
+		{repo: "acme/widgets", number: 1, title: "Panic on startup", author: "octocat"},
+		{repo: "acme/widgets", number: 2, title: "Dark mode", author: "octocat", labels: []string{"enhancement"}},
+		{repo: "acme/widgets", number: 3, title: "How to build on Windows?", author: "someone", commenters: []string{"octocat"}},
+		{repo: "other/tool", number: 9, title: "Slow builds", author: "someone", commenters: []string{"octocat", "someone"}},
+		{repo: "other/tool", number: 10, title: "Unrelated", author: "someone", commenters: []string{"maintainer"}},
+		{repo: "octocat/alpha", number: 4, title: "Own repository", author: "octocat"},
+		{repo: "acme/internal", number: 5, title: "Private crash", author: "octocat", private: true},
+		{repo: "acme/internal", number: 6, title: "Private question", author: "someone", commenters: []string{"octocat"}, private: true},
+	}
+
+	result, err := newFakeGitHubService(t, server, config.DefaultGitHubConfig()).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	activity := result.IssueActivity
+	expected := []struct {
+		number   int
+		role     string
+		category dto.IssueCategory
+	}{
+		{1, issueRoleAuthor, dto.IssueBugReport},
+		{2, issueRoleAuthor, dto.IssueFeatureRequest},
+		{3, issueRoleCommenter, dto.IssueSupportAnswer},
+		{9, issueRoleCommenter, dto.IssueSupportAnswer},
+	}
+	if len(activity.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d", len(expected), len(activity.Issues))
+	}
+	for i, want := range expected {
+		got := activity.Issues[i]
+		if got.Number != want.number || got.Role != want.role || got.Category != want.category {
+			t.Errorf("Issue %d: expected #%d %s %s, got #%d %s %s", i, want.number, want.role, want.category, got.Number, got.Role, got.Category)
+		}
+	}
+
+	if len(activity.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(activity.Repositories))
+	}
+	widgets := activity.Repositories[0]
+	if widgets.Repo != "acme/widgets" || widgets.Opened != 2 || widgets.CommentedOn != 1 ||
+		widgets.BugReports != 1 || widgets.FeatureRequests != 1 || widgets.SupportAnswers != 1 {
+		t.Errorf("Unexpected summary: %+v", widgets)
+	}
+	if tool := activity.Repositories[1]; tool.Repo != "other/tool" || tool.CommentedOn != 1 {
+		t.Errorf("Unexpected summary: %+v", tool)
+	}
+
+	for _, query := range server.searched() {
+		if strings.HasPrefix(query, "type:issue") && !strings.HasSuffix(query, " is:public") {
+			t.Errorf("Issue search %q isn't restricted to public repositories", query)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+	"dev_profiler/internal/manifests"
+)
+
+// Most dependency manifests read per repository; in a monorepo with a manifest per package, the
+// root and top-level packages are enough to name its technologies
+const maxDependencyManifests = 10
+
+// detectTechnologies builds the technology inventory of a repository from the dependency manifests
+// in its tree. Manifests are read into files, keyed by path, unless already there. Root manifests
+// are read first; vendored ones are skipped.
+func (s *GitHubService) detectTechnologies(ctx context.Context, username, repoName string, tree *github.Tree, files map[string]string) *dto.RepoTechnologies {
+	var paths []string
+	for _, entry := range tree.Entries {
+		filePath := entry.GetPath()
+		if entry.GetType() == "blob" && manifests.IsManifest(filePath) && !s.languages.IsVendored(filePath) {
+			paths = append(paths, filePath)
+		}
+	}
+	sortByDepth(paths)
+	paths = paths[:min(len(paths), maxDependencyManifests)]
+	s.fetchFiles(ctx, username, repoName, paths, files)
+
+	contents := make(map[string]string, len(paths))
+	for _, filePath := range paths {
+		contents[filePath] = files[filePath]
+	}
+	return manifests.Inventory(contents)
+}
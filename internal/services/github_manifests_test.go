@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestPerformFullAuditBuildsTechnologyInventory(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{
+			name:    "alpha",
+			commits: 1,
+			files: []string{
+				"main.go", "go.mod", "requirements.txt", "web/package.json", "vendor/github.com/acme/util/go.mod", ".github/workflows/ci.yml",
+			},
+			contents: map[string]string{
+				"go.mod":           "module example.com/alpha\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgithub.com/stretchr/testify v1.9.0\n)\n",
+				"requirements.txt": "flask>=3.0\n",
+				"web/package.json": `{"dependencies": {"react": "^18.0.0"}, "devDependencies": {"jest": "^29.0.0"}}`,
+			},
+		},
+		{
+			name:    "beta",
+			commits: 1,
+			files:   []string{"index.js", "package.json", "legacy/pom.xml"},
+			contents: map[string]string{
+				"package.json":   `{"dependencies": {"react": "^18.0.0", "left-pad": "^1.3.0"}}`,
+				"legacy/pom.xml": "<project>",
+			},
+		},
+		{
+			name:    "gamma",
+			commits: 1,
+			files:   []string{"main.c"},
+		},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.SampledRepoCount = 3
+	cfg.SampleSource = config.SampleSourceDiffs
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	inventories := make(map[string]string)
+	for _, repo := range result.RepoStats.OriginalRepos {
+		if repo.Technologies != nil {
+			inventories[repo.Name] = fmt.Sprintf("%+v", *repo.Technologies)
+		}
+	}
+	expected := map[string]string{
+		"alpha": "{Manifests:[go.mod requirements.txt web/package.json] Ecosystems:[go npm pypi] Dependencies:5 DevDependencies:1 " +
+			"Technologies:[{Name:Flask Category:web framework} {Name:Gin Category:web framework} {Name:Jest Category:testing} " +
+			"{Name:React Category:frontend} {Name:testify Category:testing}] Libraries:[]}",
+		"beta": "{Manifests:[package.json] Ecosystems:[npm] Dependencies:2 DevDependencies:0 " +
+			"Technologies:[{Name:React Category:frontend}] Libraries:[left-pad]}",
+	}
+	for name, want := range expected {
+		if inventories[name] != want {
+			t.Errorf("Technologies of %s = %s, expected %s", name, inventories[name], want)
+		}
+	}
+	if _, ok := inventories["gamma"]; ok {
+		t.Errorf("Expected no technologies for a repository without manifests, got %s", inventories["gamma"])
+	}
+
+	var usages []string
+	for _, usage := range result.Technologies {
+		usages = append(usages, fmt.Sprintf("%s:%s", usage.Name, strings.Join(usage.Repos, ",")))
+	}
+	expectedUsages := "React:alpha,beta Flask:alpha Gin:alpha Jest:alpha testify:alpha"
+	if got := strings.Join(usages, " "); got != expectedUsages {
+		t.Errorf("Technologies = %s, expected %s", got, expectedUsages)
+	}
+
+	// Manifests read for test detection aren't requested again; the vendored go.mod isn't read
+	if server.contentRequests != 6 {
+		t.Errorf("Expected 6 file content requests, got %d", server.contentRequests)
+	}
+}
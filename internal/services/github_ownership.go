@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Ownership estimate sources recorded on dto.FileAnalysis
+const (
+	ownershipBlame   = "blame"   // Share of the file's lines last changed by the user
+	ownershipCommits = "commits" // Share of the commits touching the file authored by the user
+)
+
+// Files with a smaller ownership share are flagged as mostly written by others
+const lowOwnershipThreshold = 0.2
+
+// Candidate files whose ownership is estimated per sampled file
+const ownershipCandidateFactor = 2
+
+const fileBlameQuery = `query FileBlame($owner: String!, $name: String!%s) {
+  repository(owner: $owner, name: $name) {
+    defaultBranchRef {
+      target {
+        ... on Commit {
+%s        }
+      }
+    }
+  }
+}`
+
+const fileBlameFragment = `          f%[1]d: blame(path: $p%[1]d) {
+            ranges { startingLine endingLine commit { author { user { login } } } }
+          }
+`
+
+type graphQLBlame struct {
+	Ranges []struct {
+		StartingLine int `json:"startingLine"`
+		EndingLine   int `json:"endingLine"`
+		Commit       struct {
+			Author struct {
+				User *struct {
+					Login string `json:"login"`
+				} `json:"user"`
+			} `json:"author"`
+		} `json:"commit"`
+	} `json:"ranges"`
+}
+
+// fileOwnership is the estimated share of a file written by the user
+type fileOwnership struct {
+	share  float64
+	source string // Empty when the share couldn't be estimated
+}
+
+// estimateOwnership estimates which share of each file was written by the user. Blame is used
+// when a token allows GraphQL queries, otherwise the commit history of each path.
+func (s *GitHubService) estimateOwnership(ctx context.Context, username, repoName string, paths []string) []fileOwnership {
+	if s.config.Token != "" {
+		if ownership, err := s.blameOwnership(ctx, username, repoName, paths); err == nil {
+			return ownership
+		}
+	}
+
+	// Cancellation is checked by the caller; files left without an estimate are simply not ranked
+	ownership := make([]fileOwnership, len(paths))
+	_ = runBounded(ctx, len(paths), s.config.FileConcurrency, func(i int) {
+		share, err := s.commitOwnership(ctx, username, repoName, paths[i])
+		if err == nil {
+			ownership[i] = fileOwnership{share: share, source: ownershipCommits}
+		}
+	})
+	return ownership
+}
+
+// blameOwnership blames all paths in a single GraphQL query
+func (s *GitHubService) blameOwnership(ctx context.Context, username, repoName string, paths []string) ([]fileOwnership, error) {
+	var params, fragments strings.Builder
+	variables := map[string]interface{}{"owner": username, "name": repoName}
+	for i, path := range paths {
+		fmt.Fprintf(&params, ", $p%d: String!", i)
+		fmt.Fprintf(&fragments, fileBlameFragment, i)
+		variables[fmt.Sprintf("p%d", i)] = path
+	}
+
+	var data struct {
+		Repository *struct {
+			DefaultBranchRef *struct {
+				Target map[string]*graphQLBlame `json:"target"`
+			} `json:"defaultBranchRef"`
+		} `json:"repository"`
+	}
+	query := fmt.Sprintf(fileBlameQuery, params.String(), fragments.String())
+	if _, err := s.graphQL(ctx, repoName, "file blame", query, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil || data.Repository.DefaultBranchRef == nil {
+		return nil, fmt.Errorf("repository %s/%s has no default branch", username, repoName)
+	}
+
+	ownership := make([]fileOwnership, len(paths))
+	for i := range paths {
+		blame := data.Repository.DefaultBranchRef.Target[fmt.Sprintf("f%d", i)]
+		if blame == nil {
+			continue
+		}
+
+		total, owned := 0, 0
+		for _, r := range blame.Ranges {
+			lines := r.EndingLine - r.StartingLine + 1
+			total += lines
+			if user := r.Commit.Author.User; user != nil && strings.EqualFold(user.Login, username) {
+				owned += lines
+			}
+		}
+		if total > 0 {
+			ownership[i] = fileOwnership{share: float64(owned) / float64(total), source: ownershipBlame}
+		}
+	}
+	return ownership, nil
+}
+
+// commitOwnership returns the share of the most recent commits touching a path that the user authored
+func (s *GitHubService) commitOwnership(ctx context.Context, username, repoName, path string) (float64, error) {
+	opt := &github.CommitsListOptions{
+		Path:        path,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var commits []*github.RepositoryCommit
+	err := s.callWithRetry(ctx, repoName, "file history", func() (resp *github.Response, err error) {
+		commits, resp, err = s.client.Repositories.ListCommits(ctx, username, repoName, opt)
+		return resp, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get history of %s: %w", path, err)
+	}
+	if len(commits) == 0 {
+		return 0, fmt.Errorf("no commits touch %s", path)
+	}
+
+	owned := 0
+	for _, commit := range commits {
+		if strings.EqualFold(commit.GetAuthor().GetLogin(), username) {
+			owned++
+		}
+	}
+	return float64(owned) / float64(len(commits)), nil
+}
+
+// preferOwnedFiles orders candidate files by the user's ownership share, keeping the sampled order
+// for ties. Files without an estimate rank like files at the low ownership threshold.
+func preferOwnedFiles(files []*github.TreeEntry, ownership []fileOwnership) {
+	rank := func(o fileOwnership) float64 {
+		if o.source == "" {
+			return lowOwnershipThreshold
+		}
+		return o.share
+	}
+
+	indices := make([]int, len(files))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return rank(ownership[indices[a]]) > rank(ownership[indices[b]])
+	})
+
+	sortedFiles := make([]*github.TreeEntry, len(files))
+	sortedOwnership := make([]fileOwnership, len(files))
+	for i, index := range indices {
+		sortedFiles[i] = files[index]
+		sortedOwnership[i] = ownership[index]
+	}
+	copy(files, sortedFiles)
+	copy(ownership, sortedOwnership)
+}
+
+// applyOwnership records an ownership estimate on a file analysis
+func applyOwnership(analysis *dto.FileAnalysis, ownership fileOwnership) {
+	if ownership.source == "" {
+		return
+	}
+	share := ownership.share
+	analysis.Ownership = &share
+	analysis.OwnershipSource = ownership.source
+	analysis.LowOwnership = ownership.share < lowOwnershipThreshold
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestGetRepositoryContentsPrefersOwnedFiles(t *testing.T) {
+	repos := []fakeRepo{{
+		name:      "alpha",
+		commits:   1,
+		files:     []string{"a.go", "b.go", "c.go", "d.go"},
+		ownership: map[string]int{"a.go": 1, "b.go": 9, "c.go": 0, "d.go": 5},
+	}}
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedSource string
+	}{
+		{"blame with a token", "test-token", ownershipBlame},
+		{"commit history without a token", "", ownershipCommits},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeGitHubServer(t, "octocat", repos)
+			cfg := config.DefaultGitHubConfig()
+			cfg.Token = tc.token
+			cfg.SampleFileCount = 2
+
+			files, err := newFakeGitHubService(t, server, cfg).GetRepositoryContents(context.Background(), "octocat", "alpha", nil)
+			if err != nil {
+				t.Fatalf("GetRepositoryContents() failed: %v", err)
+			}
+
+			// All four files are candidates, the two the user owns most are kept
+			if len(files) != 2 || files[0].Path != "b.go" || files[1].Path != "d.go" {
+				t.Fatalf("Expected b.go and d.go, got %+v", files)
+			}
+			if files[0].Ownership == nil || *files[0].Ownership != 0.9 || files[0].OwnershipSource != tc.expectedSource || files[0].LowOwnership {
+				t.Errorf("Unexpected ownership for b.go: %+v", files[0])
+			}
+		})
+	}
+}
+
+func TestGetRepositoryContentsFlagsLowOwnership(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{
+		name:      "alpha",
+		commits:   1,
+		files:     []string{"a.go"},
+		ownership: map[string]int{"a.go": 1},
+	}})
+
+	files, err := newFakeGitHubService(t, server, config.DefaultGitHubConfig()).GetRepositoryContents(context.Background(), "octocat", "alpha", nil)
+	if err != nil {
+		t.Fatalf("GetRepositoryContents() failed: %v", err)
+	}
+	if len(files) != 1 || !files[0].LowOwnership {
+		t.Errorf("Files the user barely touched should be flagged, got %+v", files)
+	}
+}
+
+func TestGetRepositoryContentsWithoutOwnership(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1, files: []string{"a.go"}}})
+	cfg := config.DefaultGitHubConfig()
+	cfg.EstimateOwnership = false
+
+	files, err := newFakeGitHubService(t, server, cfg).GetRepositoryContents(context.Background(), "octocat", "alpha", nil)
+	if err != nil {
+		t.Fatalf("GetRepositoryContents() failed: %v", err)
+	}
+	if len(files) != 1 || files[0].OwnershipSource != "" || files[0].Ownership != nil {
+		t.Fatalf("Ownership should not be estimated when disabled, got %+v", files)
+	}
+
+	// An unestimated share must not read as a file the user didn't write
+	data, err := json.Marshal(files[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if _, ok := fields["ownership"]; ok {
+		t.Errorf("Expected no ownership in %s", data)
+	}
+}
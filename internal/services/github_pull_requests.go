@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Maximum length of review comments kept in the audit
+const maxReviewBodyLength = 500
+
+// CollectPullRequests returns the most recent pull requests authored by the user in any public repository
+func (s *GitHubService) CollectPullRequests(ctx context.Context, username string, count int) ([]*dto.PullRequest, error) {
+	// is:public keeps out private pull requests the token can see; they must not reach the report
+	issues, err := s.searchIssues(ctx, fmt.Sprintf("type:pr author:%s is:public", username), count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search pull requests by %s: %w", username, err)
+	}
+
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressActivity,
+		Message:  fmt.Sprintf("Fetching details of %d pull requests by %s...", len(issues), username),
+		Total:    len(issues),
+		Fraction: activityProgress,
+	})
+
+	pullRequests := make([]*dto.PullRequest, len(issues))
+	errs := make([]error, len(issues))
+	var fetched int32
+	err = runBounded(ctx, len(issues), s.config.RepoConcurrency, func(i int) {
+		issue := issues[i]
+		owner, repo := issueRepository(issue)
+		pullRequests[i] = &dto.PullRequest{
+			Repo:      owner + "/" + repo,
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			State:     issue.GetState(),
+			Comments:  issue.GetComments(),
+			CreatedAt: issue.GetCreatedAt().Time,
+			URL:       issue.GetHTMLURL(),
+		}
+
+		var pr *github.PullRequest
+		errs[i] = s.callWithRetry(ctx, repo, "pull request details", func() (resp *github.Response, err error) {
+			pr, resp, err = s.client.PullRequests.Get(ctx, owner, repo, issue.GetNumber())
+			return resp, err
+		})
+		if errs[i] != nil {
+			return
+		}
+
+		details := pullRequests[i]
+		details.Merged = pr.GetMerged()
+		details.Additions = pr.GetAdditions()
+		details.Deletions = pr.GetDeletions()
+		details.ChangedFiles = pr.GetChangedFiles()
+		details.ReviewComments = pr.GetReviewComments()
+		details.MergedAt = pr.GetMergedAt().Time
+
+		s.reportProgress(ProgressEvent{
+			Kind:     ProgressActivity,
+			Message:  fmt.Sprintf("Fetched pull request %s#%d", details.Repo, details.Number),
+			Count:    int(atomic.AddInt32(&fetched, 1)),
+			Total:    len(issues),
+			Fraction: activityProgress,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pull requests without details still carry what the search returned
+	for _, err := range errs {
+		if err != nil {
+			return pullRequests, fmt.Errorf("failed to get pull request details: %w", err)
+		}
+	}
+	return pullRequests, nil
+}
+
+// CollectCodeReviews returns the reviews the user left on the most recent pull requests by other people
+func (s *GitHubService) CollectCodeReviews(ctx context.Context, username string, count int) ([]*dto.CodeReview, error) {
+	issues, err := s.searchIssues(ctx, fmt.Sprintf("type:pr reviewed-by:%s -author:%s is:public", username, username), count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search pull requests reviewed by %s: %w", username, err)
+	}
+
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressActivity,
+		Message:  fmt.Sprintf("Fetching reviews on %d pull requests...", len(issues)),
+		Total:    len(issues),
+		Fraction: activityProgress,
+	})
+
+	reviewsByPR := make([][]*dto.CodeReview, len(issues))
+	errs := make([]error, len(issues))
+	err = runBounded(ctx, len(issues), s.config.RepoConcurrency, func(i int) {
+		issue := issues[i]
+		owner, repo := issueRepository(issue)
+
+		var reviews []*github.PullRequestReview
+		errs[i] = s.callWithRetry(ctx, repo, "pull request reviews", func() (resp *github.Response, err error) {
+			reviews, resp, err = s.client.PullRequests.ListReviews(ctx, owner, repo, issue.GetNumber(), &github.ListOptions{PerPage: 100})
+			return resp, err
+		})
+		if errs[i] != nil {
+			return
+		}
+
+		for _, review := range reviews {
+			if !strings.EqualFold(review.GetUser().GetLogin(), username) {
+				continue
+			}
+			reviewsByPR[i] = append(reviewsByPR[i], &dto.CodeReview{
+				Repo:              owner + "/" + repo,
+				PullRequestNumber: issue.GetNumber(),
+				PullRequestTitle:  issue.GetTitle(),
+				PullRequestAuthor: issue.GetUser().GetLogin(),
+				State:             review.GetState(),
+				Body:              truncateText(review.GetBody(), maxReviewBodyLength),
+				SubmittedAt:       review.GetSubmittedAt().Time,
+				URL:               review.GetHTMLURL(),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var codeReviews []*dto.CodeReview
+	for _, reviews := range reviewsByPR {
+		codeReviews = append(codeReviews, reviews...)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return codeReviews, fmt.Errorf("failed to list pull request reviews: %w", err)
+		}
+	}
+	return codeReviews, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestPerformFullAuditCollectsPullRequestActivity(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1}})
+	server.pullRequests = []fakePullRequest{
+		{repo: "acme/widgets", number: 7, author: "octocat", merged: true, additions: 120, reviews: []fakeReview{
+			{user: "maintainer", state: "APPROVED"},
+		}},
+		{repo: "acme/gadgets", number: 3, author: "someone", reviews: []fakeReview{
+			{user: "octocat", state: "CHANGES_REQUESTED", body: strings.Repeat("x", maxReviewBodyLength+10)},
+			{user: "maintainer", state: "COMMENTED"},
+			{user: "octocat", state: "APPROVED"},
+		}},
+		{repo: "acme/other", number: 1, author: "someone"},
+	}
+
+	cfg := config.DefaultGitHubConfig()
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	if len(result.AnalysisSummary.CollectionErrors) != 0 {
+		t.Errorf("Unexpected collection errors: %v", result.AnalysisSummary.CollectionErrors)
+	}
+
+	if len(result.PullRequests) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(result.PullRequests))
+	}
+	pr := result.PullRequests[0]
+	if pr.Repo != "acme/widgets" || pr.Number != 7 || !pr.Merged || pr.Additions != 120 || pr.ReviewComments != 1 {
+		t.Errorf("Unexpected pull request: %+v", pr)
+	}
+
+	if len(result.CodeReviews) != 2 {
+		t.Fatalf("Expected 2 reviews by the user, got %d", len(result.CodeReviews))
+	}
+	review := result.CodeReviews[0]
+	if review.Repo != "acme/gadgets" || review.PullRequestAuthor != "someone" || review.State != "CHANGES_REQUESTED" {
+		t.Errorf("Unexpected review: %+v", review)
+	}
+	if len(review.Body) != maxReviewBodyLength+len("...") {
+		t.Errorf("Review body should be truncated, got %d characters", len(review.Body))
+	}
+}
+
+func TestPerformFullAuditPullRequestsDisabled(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1}})
+	server.pullRequests = []fakePullRequest{{repo: "acme/widgets", number: 7, author: "octocat"}}
+
+	cfg := config.DefaultGitHubConfig()
+	cfg.PullRequestCount = 0
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+	if len(result.PullRequests) != 0 || len(result.CodeReviews) != 0 {
+		t.Errorf("Pull request activity should not be collected when disabled")
+	}
+}
+
+func TestPerformFullAuditPullRequestFailureIsNotFatal(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 1}})
+	server.pullRequests = []fakePullRequest{{repo: "acme/widgets", number: 7, author: "octocat", missing: true}}
+
+	cfg := config.DefaultGitHubConfig()
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() should not fail when pull requests can't be collected: %v", err)
+	}
+	if len(result.AnalysisSummary.CollectionErrors) != 1 {
+		t.Errorf("Expected one collection error, got %v", result.AnalysisSummary.CollectionErrors)
+	}
+	if len(result.PullRequests) != 1 || result.PullRequests[0].Title != "PR 7" {
+		t.Errorf("Search results should be kept when details are missing: %+v", result.PullRequests)
+	}
+	if len(result.AnalysisSummary.ReposAnalyzedForCode) != 1 {
+		t.Errorf("Repository analysis should continue, got %v", result.AnalysisSummary.ReposAnalyzedForCode)
+	}
+}
+
+func TestCollectPullRequestActivitySearchesPublicRepositories(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", nil)
+	service := newFakeGitHubService(t, server, config.DefaultGitHubConfig())
+
+	if _, err := service.CollectPullRequests(context.Background(), "octocat", 5); err != nil {
+		t.Fatalf("CollectPullRequests() failed: %v", err)
+	}
+	if _, err := service.CollectCodeReviews(context.Background(), "octocat", 5); err != nil {
+		t.Fatalf("CollectCodeReviews() failed: %v", err)
+	}
+
+	expected := []string{
+		"type:pr author:octocat is:public",
+		"type:pr reviewed-by:octocat -author:octocat is:public",
+	}
+	queries := server.searched()
+	if len(queries) != len(expected) {
+		t.Fatalf("Expected %d search queries, got %v", len(expected), queries)
+	}
+	for i, query := range queries {
+		if query != expected[i] {
+			t.Errorf("Search query %d = %q, expected %q", i, query, expected[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+	"dev_profiler/internal/httpcache"
+)
+
+// Maximum number of rate-limit pauses for a single GitHub API call
+const maxRateLimitWaits = 5
+
+// Longest pause accepted before giving up on an exhausted quota
+var maxRateLimitWait = time.Hour
+
+// Pause used for secondary rate limits that do not specify a Retry-After delay
+var secondaryRateLimitDelay = time.Minute
+
+// Extra time added to the reset time reported by GitHub to absorb clock skew
+var rateLimitResetMargin = 2 * time.Second
+
+// resetAPIUsage clears the API usage counters at the start of an audit
+func (s *GitHubService) resetAPIUsage() {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	s.usage = dto.APIUsage{}
+}
+
+// APIUsage returns the GitHub API usage recorded since the last audit started
+func (s *GitHubService) APIUsage() dto.APIUsage {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	return s.usage
+}
+
+// recordAPICall counts a GitHub API call and remembers the quota reported by its response
+func (s *GitHubService) recordAPICall(resp *github.Response, err error) {
+	rate := github.Rate{}
+	var rateErr *github.RateLimitError
+	switch {
+	case resp != nil && resp.Rate.Limit > 0:
+		rate = resp.Rate
+	case errors.As(err, &rateErr):
+		rate = rateErr.Rate
+	}
+
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+
+	s.usage.Calls++
+	if resp != nil && resp.Response != nil && resp.Header.Get(httpcache.HeaderFromCache) != "" {
+		s.usage.CachedResponses++
+	}
+	if rate.Limit > 0 {
+		s.usage.RateLimit = rate.Limit
+		s.usage.RateLimitRemaining = rate.Remaining
+		s.usage.RateLimitReset = rate.Reset.Time
+	}
+}
+
+// recordCommitCountCalls records the API calls spent counting the user's commits per repository
+func (s *GitHubService) recordCommitCountCalls(calls int) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	s.usage.CommitCountCalls += calls
+}
+
+// recordRateLimitWait adds a rate-limit pause to the usage counters
+func (s *GitHubService) recordRateLimitWait(wait time.Duration) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	s.usage.RateLimitWaits++
+	s.usage.RateLimitWaitTime += wait.Seconds()
+}
+
+// rateLimitWait returns how long to pause before retrying a call rejected by a rate limit.
+// It returns false when the error is not a rate limit or the pause would be too long.
+func rateLimitWait(err error, now time.Time) (time.Duration, string, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		wait := rateErr.Rate.Reset.Time.Sub(now) + rateLimitResetMargin
+		if wait < rateLimitResetMargin {
+			wait = rateLimitResetMargin
+		}
+		if wait > maxRateLimitWait {
+			return 0, "", false
+		}
+		return wait, fmt.Sprintf("GitHub API quota exhausted (%d requests/hour)", rateErr.Rate.Limit), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		wait := secondaryRateLimitDelay
+		if abuseErr.RetryAfter != nil {
+			wait = *abuseErr.RetryAfter
+		}
+		if wait > maxRateLimitWait {
+			return 0, "", false
+		}
+		return wait, "GitHub secondary rate limit hit", true
+	}
+
+	return 0, "", false
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/httpcache"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	retryAfter := 30 * time.Second
+
+	testCases := []struct {
+		name string
+		err  error
+		wait time.Duration
+		ok   bool
+	}{
+		{
+			name: "primary limit waits until reset",
+			err:  &github.RateLimitError{Rate: github.Rate{Limit: 5000, Reset: github.Timestamp{Time: now.Add(10 * time.Minute)}}},
+			wait: 10*time.Minute + rateLimitResetMargin,
+			ok:   true,
+		},
+		{
+			name: "primary limit already reset",
+			err:  &github.RateLimitError{Rate: github.Rate{Limit: 5000, Reset: github.Timestamp{Time: now.Add(-time.Minute)}}},
+			wait: rateLimitResetMargin,
+			ok:   true,
+		},
+		{
+			name: "primary limit resets too late",
+			err:  &github.RateLimitError{Rate: github.Rate{Limit: 60, Reset: github.Timestamp{Time: now.Add(2 * time.Hour)}}},
+			ok:   false,
+		},
+		{
+			name: "secondary limit uses Retry-After",
+			err:  &github.AbuseRateLimitError{RetryAfter: &retryAfter},
+			wait: retryAfter,
+			ok:   true,
+		},
+		{
+			name: "secondary limit without Retry-After",
+			err:  &github.AbuseRateLimitError{},
+			wait: secondaryRateLimitDelay,
+			ok:   true,
+		},
+		{
+			name: "other errors are not rate limits",
+			err:  errors.New("not found"),
+			ok:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wait, reason, ok := rateLimitWait(tc.err, now)
+			if ok != tc.ok {
+				t.Fatalf("Expected ok=%v, got %v", tc.ok, ok)
+			}
+			if wait != tc.wait {
+				t.Errorf("Expected wait %s, got %s", tc.wait, wait)
+			}
+			if ok && reason == "" {
+				t.Error("Expected a reason for the pause")
+			}
+		})
+	}
+}
+
+func TestCallWithRetryWaitsForRateLimit(t *testing.T) {
+	originalMargin := rateLimitResetMargin
+	rateLimitResetMargin = time.Millisecond
+	defer func() { rateLimitResetMargin = originalMargin }()
+
+	service := NewGitHubService(&config.GitHubConfig{})
+	var pauses []ProgressEvent
+	service.SetProgressCallback(func(event ProgressEvent) {
+		if event.Kind == ProgressRateLimit {
+			pauses = append(pauses, event)
+		}
+	})
+
+	limited := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusForbidden},
+		Rate:     github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: time.Now()}},
+	}
+	ok := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusOK},
+		Rate:     github.Rate{Limit: 5000, Remaining: 4999},
+	}
+
+	calls := 0
+	err := service.callWithRetry(context.Background(), "repo", "test call", func() (*github.Response, error) {
+		calls++
+		// Exceed the retry attempts to check that rate-limit pauses are not counted as attempts
+		if calls <= maxCallAttempts {
+			return limited, &github.RateLimitError{Rate: limited.Rate, Response: limited.Response}
+		}
+		return ok, nil
+	})
+	if err != nil {
+		t.Fatalf("callWithRetry() failed: %v", err)
+	}
+
+	if calls != maxCallAttempts+1 {
+		t.Errorf("Expected %d calls, got %d", maxCallAttempts+1, calls)
+	}
+	if len(pauses) != maxCallAttempts {
+		t.Errorf("Expected %d rate-limit events, got %d", maxCallAttempts, len(pauses))
+	}
+
+	usage := service.APIUsage()
+	if usage.Calls != calls {
+		t.Errorf("Expected %d recorded calls, got %d", calls, usage.Calls)
+	}
+	if usage.RateLimitWaits != maxCallAttempts {
+		t.Errorf("Expected %d recorded waits, got %d", maxCallAttempts, usage.RateLimitWaits)
+	}
+	if usage.RateLimit != 5000 || usage.RateLimitRemaining != 4999 {
+		t.Errorf("Expected quota from the last response, got %d of %d", usage.RateLimitRemaining, usage.RateLimit)
+	}
+}
+
+func TestCallWithRetryGivesUpOnLongRateLimit(t *testing.T) {
+	service := NewGitHubService(&config.GitHubConfig{})
+	rateErr := &github.RateLimitError{Rate: github.Rate{Limit: 60, Reset: github.Timestamp{Time: time.Now().Add(3 * time.Hour)}}}
+
+	calls := 0
+	err := service.callWithRetry(context.Background(), "repo", "test call", func() (*github.Response, error) {
+		calls++
+		return nil, rateErr
+	})
+
+	if !errors.Is(err, rateErr) {
+		t.Errorf("Expected the rate limit error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
+
+func TestPerformFullAuditRecordsAPIUsage(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go", "b.go"}},
+	})
+
+	result, err := newFakeGitHubService(t, server, config.DefaultGitHubConfig()).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	usage := result.AnalysisSummary.APIUsage
+	if usage.Calls != int(server.requests) {
+		t.Errorf("Expected %d API calls, got %d", server.requests, usage.Calls)
+	}
+	if usage.RateLimit != 5000 {
+		t.Errorf("Expected rate limit 5000, got %d", usage.RateLimit)
+	}
+	if usage.RateLimitRemaining < 5000-usage.Calls {
+		t.Errorf("Remaining quota %d is lower than expected", usage.RateLimitRemaining)
+	}
+}
+
+func TestPerformFullAuditUsesResponseCache(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go", "b.go"}},
+	})
+	service := newFakeGitHubService(t, server, config.DefaultGitHubConfig())
+	useTransport(service, httpcache.NewTransport(t.TempDir(), time.Hour, server.Client().Transport))
+
+	first, err := service.PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+	served := server.requests
+
+	second, err := service.PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	if server.requests != served {
+		t.Errorf("Second audit should be served from the cache, server got %d extra requests", server.requests-served)
+	}
+	if first.AnalysisSummary.APIUsage.CachedResponses != 0 {
+		t.Errorf("First audit should not use the cache, got %d cached responses", first.AnalysisSummary.APIUsage.CachedResponses)
+	}
+	usage := second.AnalysisSummary.APIUsage
+	if usage.CachedResponses != usage.Calls {
+		t.Errorf("Expected all %d calls to be cached, got %d", usage.Calls, usage.CachedResponses)
+	}
+	if len(second.CommitDetails) != len(first.CommitDetails) || len(second.FileAnalysis) != len(first.FileAnalysis) {
+		t.Error("Cached audit should produce the same data")
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Maximum number of attempts for a single GitHub API call
+const maxCallAttempts = 3
+
+// Delay before the first retry, doubled for each subsequent attempt
+var retryBaseDelay = 2 * time.Second
+
+// callWithRetry runs a GitHub API call, retrying transient failures with exponential backoff.
+// Calls rejected by a primary or secondary rate limit are paused until the limit resets
+// and do not count towards the retry attempts.
+// The call closure is expected to capture its results and return the API response and error.
+func (s *GitHubService) callWithRetry(ctx context.Context, repo, description string, call func() (*github.Response, error)) error {
+	delay := retryBaseDelay
+	attempt := 1
+	rateLimitWaits := 0
+	for {
+		resp, err := call()
+		s.recordAPICall(resp, err)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		if wait, reason, ok := rateLimitWait(err, time.Now()); ok && rateLimitWaits < maxRateLimitWaits {
+			rateLimitWaits++
+			s.recordRateLimitWait(wait)
+			s.reportProgress(ProgressEvent{
+				Kind:    ProgressRateLimit,
+				Message: fmt.Sprintf("%s, pausing %s before resuming %s...", reason, wait.Round(time.Second), description),
+				Repo:    repo,
+				Wait:    wait,
+			})
+
+			if err := sleepContext(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if attempt == maxCallAttempts || !isTransientError(resp, err) {
+			return err
+		}
+
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressRetry,
+			Message: fmt.Sprintf("Retrying %s in %s (attempt %d of %d): %v", description, delay, attempt+1, maxCallAttempts, err),
+			Repo:    repo,
+			Attempt: attempt + 1,
+			Wait:    delay,
+		})
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+		delay *= 2
+		attempt++
+	}
+}
+
+// isTransientError reports whether a failed call is worth retrying
+func isTransientError(resp *github.Response, err error) bool {
+	if resp != nil && resp.Response != nil {
+		return resp.StatusCode >= 500
+	}
+
+	// No response means the request never completed (connection reset, timeout, DNS)
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// sleepContext waits for the given duration or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
+)
+
+// Size limits for sampled files; smaller files such as empty __init__.py say little about the
+// author, larger ones are usually generated or bundled
+const (
+	minSampleFileSize = 100
+	maxSampleFileSize = 100 * 1024
+)
+
+// Most files without an extension read per repository to find scripts by their shebang
+const maxShebangChecks = 10
+
+// File names of entry points, preferred by the entry points strategy
+var entryPointNames = map[string]bool{
+	"main.go": true, "main.py": true, "__main__.py": true, "app.py": true, "manage.py": true,
+	"index.js": true, "index.ts": true, "app.js": true, "app.ts": true, "server.js": true, "server.ts": true,
+	"main.rs": true, "lib.rs": true, "main.c": true, "main.cpp": true, "main.cc": true,
+	"main.java": true, "application.java": true, "program.cs": true, "main.kt": true,
+	"main.swift": true, "appdelegate.swift": true, "main.rb": true, "index.php": true,
+}
+
+// isSampleCandidate reports whether a path is hand-written code: a programming language file
+// that isn't vendored, generated or excluded in the configuration
+func (s *GitHubService) isSampleCandidate(filePath string) bool {
+	return s.languages.IsCode(filePath) && !s.languages.IsExcluded(filePath)
+}
+
+// isScriptCandidate reports whether a path without an extension may be a hand-written script
+// that only its shebang identifies, such as bin/deploy
+func (s *GitHubService) isScriptCandidate(filePath string) bool {
+	return s.languages.MayBeScript(filePath) && !s.languages.IsExcluded(filePath)
+}
+
+// shebangScripts reads files that may be scripts, the shallowest first and at most
+// maxShebangChecks of them, and returns those whose shebang names a programming language.
+// The contents read are stored in fetched, keyed by path.
+func (s *GitHubService) shebangScripts(ctx context.Context, username, repoName string, candidates []*github.TreeEntry, fetched map[string]string) []*github.TreeEntry {
+	byPath := make(map[string]*github.TreeEntry, len(candidates))
+	paths := make([]string, 0, len(candidates))
+	for _, entry := range candidates {
+		byPath[entry.GetPath()] = entry
+		paths = append(paths, entry.GetPath())
+	}
+	sortByDepth(paths)
+	paths = paths[:min(len(paths), maxShebangChecks)]
+	s.fetchFiles(ctx, username, repoName, paths, fetched)
+
+	var scripts []*github.TreeEntry
+	for _, filePath := range paths {
+		if content := fetched[filePath]; content != "" && s.languages.IsCodeContent(filePath, content) {
+			scripts = append(scripts, byPath[filePath])
+		} else {
+			delete(fetched, filePath)
+		}
+	}
+	return scripts
+}
+
+// hasSampleSize reports whether a file's size is within the sampling limits. Files whose size
+// the tree doesn't report are kept.
+func hasSampleSize(entry *github.TreeEntry) bool {
+	return entry.Size == nil || entry.GetSize() >= minSampleFileSize && entry.GetSize() <= maxSampleFileSize
+}
+
+// isEntryPoint reports whether a file starts a program or library
+func isEntryPoint(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return entryPointNames[path.Base(lower)] || strings.HasPrefix(lower, "cmd/") || strings.Contains(lower, "/cmd/")
+}
+
+// changeCounts counts how often each file was changed by the given commits
+func changeCounts(commits []*dto.CommitDetail) map[string]int {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		for _, file := range commit.FilesChanged {
+			counts[file]++
+		}
+	}
+	return counts
+}
+
+// orderSampleCandidates orders candidate files by preference of the configured sampling strategy.
+// Files are shuffled first so ties are broken by the random seed.
+func (s *GitHubService) orderSampleCandidates(files []*github.TreeEntry, commits []*dto.CommitDetail) {
+	// Use a private source so concurrent repositories get the same sample as a serial run
+	rng := rand.New(rand.NewSource(int64(s.config.RandomSeed)))
+	rng.Shuffle(len(files), func(i, j int) {
+		files[i], files[j] = files[j], files[i]
+	})
+
+	switch s.config.SamplingStrategy {
+	case config.SamplingLargest:
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].GetSize() > files[j].GetSize()
+		})
+	case config.SamplingFrequent:
+		changes := changeCounts(commits)
+		sort.SliceStable(files, func(i, j int) bool {
+			return changes[files[i].GetPath()] > changes[files[j].GetPath()]
+		})
+	case config.SamplingEntryPoints:
+		sort.SliceStable(files, func(i, j int) bool {
+			return isEntryPoint(files[i].GetPath()) && !isEntryPoint(files[j].GetPath())
+		})
+	case config.SamplingLanguages:
+		interleaveFiles(files, func(filePath string) string {
+			return s.languages.Detect(filePath, "")
+		})
+	case config.SamplingDirectories:
+		interleaveFiles(files, func(filePath string) string {
+			dir, _, found := strings.Cut(filePath, "/")
+			if !found {
+				return ""
+			}
+			return dir
+		})
+	}
+}
+
+// interleaveFiles reorders files to take one file from each group in turn, starting with the
+// largest group. Files keep their order within a group.
+func interleaveFiles(files []*github.TreeEntry, group func(filePath string) string) {
+	var keys []string
+	groups := make(map[string][]*github.TreeEntry)
+	for _, file := range files {
+		key := group(file.GetPath())
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], file)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(groups[keys[i]]) > len(groups[keys[j]])
+	})
+
+	ordered := files[:0:0]
+	for round := 0; len(ordered) < len(files); round++ {
+		for _, key := range keys {
+			if round < len(groups[key]) {
+				ordered = append(ordered, groups[key][round])
+			}
+		}
+	}
+	copy(files, ordered)
+}
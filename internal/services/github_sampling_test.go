@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
+)
+
+func TestIsSampleCandidate(t *testing.T) {
+	cfg := config.DefaultGitHubConfig()
+	cfg.LanguageOverrides = map[string]string{".tpl": "Go", ".sh": ""}
+	cfg.ExcludedPaths = []string{"legacy/*"}
+	service := NewGitHubService(cfg)
+
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"main.go", true},
+		{"web/src/App.tsx", true},
+		{"templates/page.tpl", true},
+		{"scripts/deploy.sh", false},
+		{"legacy/old.go", false},
+		{"README.md", false},
+		{"vendor/github.com/pkg/errors/errors.go", false},
+		{"internal/parser/testdata/input.go", false},
+		{"static/app.min.js", false},
+		{"api/service.pb.go", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := service.isSampleCandidate(tc.path); got != tc.expected {
+				t.Errorf("isSampleCandidate(%q) = %v, want %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHasSampleSize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		size     *int
+		expected bool
+	}{
+		{"unknown size", nil, true},
+		{"regular file", github.Int(4000), true},
+		{"empty __init__.py", github.Int(0), false},
+		{"bundle", github.Int(500 * 1024), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := hasSampleSize(&github.TreeEntry{Size: tc.size}); got != tc.expected {
+				t.Errorf("hasSampleSize() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOrderSampleCandidates(t *testing.T) {
+	entries := func() []*github.TreeEntry {
+		return []*github.TreeEntry{
+			{Path: github.String("internal/util.go"), Size: github.Int(300)},
+			{Path: github.String("internal/store.go"), Size: github.Int(9000)},
+			{Path: github.String("cmd/tool/main.go"), Size: github.Int(600)},
+			{Path: github.String("scripts/build.py"), Size: github.Int(800)},
+		}
+	}
+	commits := []*dto.CommitDetail{
+		{FilesChanged: []string{"internal/util.go", "README.md"}},
+		{FilesChanged: []string{"internal/util.go"}},
+	}
+
+	testCases := []struct {
+		strategy string
+		first    string
+		second   string
+	}{
+		{config.SamplingLargest, "internal/store.go", "scripts/build.py"},
+		{config.SamplingFrequent, "internal/util.go", ""},
+		{config.SamplingEntryPoints, "cmd/tool/main.go", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.strategy, func(t *testing.T) {
+			cfg := config.DefaultGitHubConfig()
+			cfg.SamplingStrategy = tc.strategy
+			files := entries()
+			NewGitHubService(cfg).orderSampleCandidates(files, commits)
+
+			if files[0].GetPath() != tc.first {
+				t.Errorf("Expected %s first, got %s", tc.first, files[0].GetPath())
+			}
+			if tc.second != "" && files[1].GetPath() != tc.second {
+				t.Errorf("Expected %s second, got %s", tc.second, files[1].GetPath())
+			}
+		})
+	}
+
+	// Spreading strategies cover every group before repeating one
+	spreading := []struct {
+		strategy string
+		distinct func(files []*github.TreeEntry) bool
+	}{
+		{config.SamplingLanguages, func(files []*github.TreeEntry) bool {
+			return files[1].GetPath() == "scripts/build.py"
+		}},
+		{config.SamplingDirectories, func(files []*github.TreeEntry) bool {
+			seen := make(map[string]bool)
+			for _, file := range files[:3] {
+				dir := file.GetPath()[:3]
+				if seen[dir] {
+					return false
+				}
+				seen[dir] = true
+			}
+			return true
+		}},
+	}
+	for _, tc := range spreading {
+		t.Run(tc.strategy, func(t *testing.T) {
+			cfg := config.DefaultGitHubConfig()
+			cfg.SamplingStrategy = tc.strategy
+			files := entries()
+			NewGitHubService(cfg).orderSampleCandidates(files, commits)
+
+			if !tc.distinct(files) {
+				t.Errorf("Expected the first files to come from different groups, got %v", files)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryContentsSkipsVendoredFiles(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{
+		name:    "alpha",
+		commits: 1,
+		files:   []string{"vendor/lib/lib.go", "api/api.pb.go", "main.go", "testdata/case.go"},
+	}})
+	cfg := config.DefaultGitHubConfig()
+	cfg.EstimateOwnership = false
+
+	files, err := newFakeGitHubService(t, server, cfg).GetRepositoryContents(context.Background(), "octocat", "alpha", nil)
+	if err != nil {
+		t.Fatalf("GetRepositoryContents() failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "main.go" {
+		t.Errorf("Expected only main.go to be sampled, got %+v", files)
+	}
+}
+
+func TestGetRepositoryContentsSamplesShebangScripts(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{
+		name:    "alpha",
+		commits: 1,
+		files:   []string{"bin/deploy", "bin/notes", "LICENSE", "main.go"},
+		contents: map[string]string{
+			"bin/deploy": "#!/usr/bin/env bash\nset -euo pipefail\n",
+			"bin/notes":  "Release notes live in the wiki\n",
+		},
+	}})
+	cfg := config.DefaultGitHubConfig()
+	cfg.EstimateOwnership = false
+
+	files, err := newFakeGitHubService(t, server, cfg).GetRepositoryContents(context.Background(), "octocat", "alpha", nil)
+	if err != nil {
+		t.Fatalf("GetRepositoryContents() failed: %v", err)
+	}
+	languages := make(map[string]string)
+	for _, file := range files {
+		languages[file.Path] = file.Language
+	}
+	expected := map[string]string{"main.go": "Go", "bin/deploy": "Shell"}
+	if len(languages) != len(expected) {
+		t.Errorf("Expected %v to be sampled, got %v", expected, languages)
+	}
+	for filePath, language := range expected {
+		if languages[filePath] != language {
+			t.Errorf("Language of %s = %q, expected %q", filePath, languages[filePath], language)
+		}
+	}
+	// The script is read once; LICENSE is never read
+	if server.contentRequests != 3 {
+		t.Errorf("Expected 3 file content requests, got %d", server.contentRequests)
+	}
+}
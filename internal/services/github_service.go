@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
-	"math/rand"
-	"path/filepath"
+	"net/http"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v62/github"
@@ -15,37 +18,87 @@ import (
 
 	"dev_profiler/internal/config"
 	"dev_profiler/internal/dto"
+	"dev_profiler/internal/httpcache"
+	"dev_profiler/internal/languages"
+	"dev_profiler/internal/manifests"
+	"dev_profiler/internal/metrics"
 )
 
 // GitHubService handles GitHub API interactions
 type GitHubService struct {
 	client *github.Client
 	config *config.GitHubConfig
+
+	progressMu   sync.Mutex
+	progress     ProgressFunc
+	tracker      *auditProgress
+	lastFraction float64 // Fraction of the last event, for events reported without one
+
+	usageMu sync.Mutex
+	usage   dto.APIUsage
+
+	collector collector
+	languages *languages.Registry // Language detection and code file filters, with the configured overrides
+
+	viewerMu    sync.Mutex
+	viewerLogin string // Login of the token owner, looked up on first use
 }
 
 // NewGitHubService creates a new GitHub service
 func NewGitHubService(config *config.GitHubConfig) *GitHubService {
 	var client *github.Client
-	
+	httpClient := newHTTPClient(config)
+
 	if config.Token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: config.Token},
 		)
-		tc := oauth2.NewClient(context.Background(), ts)
+		// The token is added on top of the caching transport
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		tc := oauth2.NewClient(ctx, ts)
 		client = github.NewClient(tc)
 	} else {
-		client = github.NewClient(nil)
+		client = github.NewClient(httpClient)
 	}
 
-	return &GitHubService{
-		client: client,
-		config: config,
+	service := &GitHubService{
+		client:    client,
+		config:    config,
+		languages: languages.New(config.LanguageOverrides, config.ExcludedPaths),
 	}
+	service.collector = newCollector(service)
+	return service
+}
+
+// newCollector creates the data collector selected in the configuration
+func newCollector(service *GitHubService) collector {
+	if service.config.CollectorBackend == config.CollectorGraphQL {
+		return newGraphQLCollector(service)
+	}
+	return restCollector{service}
+}
+
+// newHTTPClient creates the HTTP client for GitHub API calls, caching responses on disk when enabled
+func newHTTPClient(cfg *config.GitHubConfig) *http.Client {
+	if !cfg.CacheEnabled {
+		return &http.Client{}
+	}
+
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return &http.Client{}
+	}
+	ttl := time.Duration(cfg.CacheTTLHours) * time.Hour
+	return &http.Client{Transport: httpcache.NewTransport(cacheDir, ttl, http.DefaultTransport)}
 }
 
 // GetUser retrieves GitHub user information
 func (s *GitHubService) GetUser(ctx context.Context, username string) (*dto.UserInfo, error) {
-	user, _, err := s.client.Users.Get(ctx, username)
+	var user *github.User
+	err := s.callWithRetry(ctx, "", "user lookup", func() (resp *github.Response, err error) {
+		user, resp, err = s.client.Users.Get(ctx, username)
+		return resp, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user %s: %w", username, err)
 	}
@@ -79,14 +132,34 @@ func (s *GitHubService) GetUser(ctx context.Context, username string) (*dto.User
 func (s *GitHubService) ListRepositories(ctx context.Context, username string) ([]*dto.Repository, error) {
 	opt := &github.RepositoryListOptions{
 		Type:        "all",
-		Sort:        "updated",
+		Sort:        "pushed",
 		Direction:   "desc",
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
 
+	listUser := username
+	if s.includePrivateRepos(ctx, username) {
+		// Only the authenticated endpoint returns private repositories
+		listUser = ""
+		opt.Type = ""
+		opt.Visibility = "all"
+		opt.Affiliation = "owner,collaborator"
+		if s.config.OwnedReposOnly {
+			opt.Affiliation = "owner"
+		}
+	} else if s.config.OwnedReposOnly {
+		opt.Type = "owner"
+	}
+
 	var allRepos []*github.Repository
 	for {
-		repos, resp, err := s.client.Repositories.List(ctx, username, opt)
+		var repos []*github.Repository
+		var resp *github.Response
+		err := s.callWithRetry(ctx, "", "repository listing", func() (r *github.Response, err error) {
+			repos, r, err = s.client.Repositories.List(ctx, listUser, opt)
+			resp = r
+			return r, err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list repositories: %w", err)
 		}
@@ -102,10 +175,21 @@ func (s *GitHubService) ListRepositories(ctx context.Context, username string) (
 	var repositories []*dto.Repository
 	for _, repo := range allRepos {
 		repository := &dto.Repository{
-			Name:        repo.GetName(),
-			Description: repo.GetDescription(),
-			Stars:       repo.GetStargazersCount(),
-			Fork:        repo.GetFork(),
+			Name:          repo.GetName(),
+			Description:   repo.GetDescription(),
+			Stars:         repo.GetStargazersCount(),
+			Watchers:      repo.GetWatchersCount(),
+			ForksCount:    repo.GetForksCount(),
+			OpenIssues:    repo.GetOpenIssuesCount(),
+			SizeKB:        repo.GetSize(),
+			Topics:        repo.Topics,
+			License:       repo.GetLicense().GetSPDXID(),
+			Homepage:      repo.GetHomepage(),
+			DefaultBranch: repo.GetDefaultBranch(),
+			Fork:          repo.GetFork(),
+			Private:       repo.GetPrivate(),
+			Archived:      repo.GetArchived(),
+			Disabled:      repo.GetDisabled(),
 		}
 
 		if repo.CreatedAt != nil {
@@ -114,6 +198,9 @@ func (s *GitHubService) ListRepositories(ctx context.Context, username string) (
 		if repo.UpdatedAt != nil {
 			repository.UpdatedAt = repo.UpdatedAt.Time
 		}
+		if repo.PushedAt != nil {
+			repository.PushedAt = repo.PushedAt.Time
+		}
 
 		if repo.GetFork() && repo.Parent != nil {
 			repository.ForkSource = repo.Parent.GetFullName()
@@ -125,9 +212,49 @@ func (s *GitHubService) ListRepositories(ctx context.Context, username string) (
 	return repositories, nil
 }
 
+// includePrivateRepos reports whether private repositories should be listed for the user.
+// GitHub only exposes private repositories to their owner, so the token must belong to the audited user.
+func (s *GitHubService) includePrivateRepos(ctx context.Context, username string) bool {
+	if !s.config.IncludePrivateRepo || s.config.Token == "" {
+		return false
+	}
+
+	login, err := s.authenticatedLogin(ctx)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(login, username)
+}
+
+// authenticatedLogin returns the login of the token owner
+func (s *GitHubService) authenticatedLogin(ctx context.Context) (string, error) {
+	s.viewerMu.Lock()
+	defer s.viewerMu.Unlock()
+
+	if s.viewerLogin != "" {
+		return s.viewerLogin, nil
+	}
+
+	var user *github.User
+	err := s.callWithRetry(ctx, "", "token owner lookup", func() (resp *github.Response, err error) {
+		user, resp, err = s.client.Users.Get(ctx, "")
+		return resp, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	s.viewerLogin = user.GetLogin()
+	return s.viewerLogin, nil
+}
+
 // GetRepositoryLanguages retrieves languages used in a repository
 func (s *GitHubService) GetRepositoryLanguages(ctx context.Context, username, repoName string) ([]string, error) {
-	languages, _, err := s.client.Repositories.ListLanguages(ctx, username, repoName)
+	var languages map[string]int
+	err := s.callWithRetry(ctx, repoName, "language lookup", func() (resp *github.Response, err error) {
+		languages, resp, err = s.client.Repositories.ListLanguages(ctx, username, repoName)
+		return resp, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get languages for %s/%s: %w", username, repoName, err)
 	}
@@ -152,334 +279,580 @@ func (s *GitHubService) GetRepositoryCommits(ctx context.Context, username, repo
 		ListOptions: github.ListOptions{PerPage: count},
 	}
 
-	commits, _, err := s.client.Repositories.ListCommits(ctx, username, repoName, opt)
+	var commits []*github.RepositoryCommit
+	var resp *github.Response
+	err := s.callWithRetry(ctx, repoName, "commit listing", func() (r *github.Response, err error) {
+		commits, r, err = s.client.Repositories.ListCommits(ctx, username, repoName, opt)
+		resp = r
+		return r, err
+	})
 	if err != nil {
+		// Empty repositories report a conflict instead of an empty list
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			err = errEmptyRepository
+		}
 		return nil, fmt.Errorf("failed to get commits for %s/%s: %w", username, repoName, err)
 	}
 
-	var commitDetails []*dto.CommitDetail
-	for _, commit := range commits {
-		detail := &dto.CommitDetail{
-			Repo:    repoName,
-			SHA:     commit.GetSHA(),
-			Message: commit.GetCommit().GetMessage(),
-			Author:  commit.GetCommit().GetAuthor().GetName(),
+	commitDetails := make([]*dto.CommitDetail, len(commits))
+	for i, commit := range commits {
+		commitDetails[i] = &dto.CommitDetail{
+			Repo:        repoName,
+			SHA:         commit.GetSHA(),
+			Message:     commit.GetCommit().GetMessage(),
+			Author:      commit.GetCommit().GetAuthor().GetName(),
+			AuthorEmail: commit.GetCommit().GetAuthor().GetEmail(),
+			MatchedBy:   dto.MatchedByLogin,
 		}
 
 		if commit.GetCommit().GetAuthor().Date != nil {
-			detail.Date = commit.GetCommit().GetAuthor().Date.Time
+			commitDetails[i].Date = commit.GetCommit().GetAuthor().Date.Time
 		}
+	}
+
+	if err := s.fetchChangedFiles(ctx, username, repoName, commitDetails); err != nil {
+		return nil, err
+	}
+
+	return commitDetails, nil
+}
+
+// fetchChangedFiles fills in the files and lines changed by each commit, fetching commit details
+// concurrently
+func (s *GitHubService) fetchChangedFiles(ctx context.Context, username, repoName string, commits []*dto.CommitDetail) error {
+	s.reportProgress(ProgressEvent{
+		Kind:    ProgressCommits,
+		Message: fmt.Sprintf("%s: found %d commits by %s", repoName, len(commits), username),
+		Repo:    repoName,
+		Total:   len(commits),
+	})
 
-		// Get files changed in this commit
-		commitDetail, _, err := s.client.Repositories.GetCommit(ctx, username, repoName, commit.GetSHA(), nil)
+	var fetched int32
+	return runBounded(ctx, len(commits), s.config.CommitConcurrency, func(i int) {
+		commit := commits[i]
+
+		var commitDetail *github.RepositoryCommit
+		err := s.callWithRetry(ctx, repoName, "commit details", func() (resp *github.Response, err error) {
+			commitDetail, resp, err = s.client.Repositories.GetCommit(ctx, username, repoName, commit.SHA, nil)
+			return resp, err
+		})
 		if err == nil && commitDetail.Files != nil {
 			for _, file := range commitDetail.Files {
-				detail.FilesChanged = append(detail.FilesChanged, file.GetFilename())
+				commit.FilesChanged = append(commit.FilesChanged, file.GetFilename())
+				commit.Additions += file.GetAdditions()
+				commit.Deletions += file.GetDeletions()
+				if s.config.SampleSource == config.SampleSourceDiffs && file.GetPatch() != "" && s.isSampleCandidate(file.GetFilename()) {
+					commit.Patches = append(commit.Patches, &dto.DiffSample{
+						Repo:      repoName,
+						SHA:       commit.SHA,
+						Path:      file.GetFilename(),
+						Language:  s.languages.Detect(file.GetFilename(), ""),
+						Additions: file.GetAdditions(),
+						Deletions: file.GetDeletions(),
+						Patch:     file.GetPatch(),
+					})
+				}
+			}
+			// The totals also cover files beyond the first 300, which the API doesn't list
+			if stats := commitDetail.GetStats(); stats != nil {
+				commit.Additions = stats.GetAdditions()
+				commit.Deletions = stats.GetDeletions()
 			}
 		}
 
-		commitDetails = append(commitDetails, detail)
+		done := int(atomic.AddInt32(&fetched, 1))
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressCommits,
+			Message: fmt.Sprintf("%s: fetched commit %d of %d", repoName, done, len(commits)),
+			Repo:    repoName,
+			Count:   done,
+			Total:   len(commits),
+		})
+	})
+}
+
+// getTree retrieves the recursive file tree of a repository's default branch
+func (s *GitHubService) getTree(ctx context.Context, username, repoName string) (*github.Tree, error) {
+	var tree *github.Tree
+	err := s.callWithRetry(ctx, repoName, "repository tree", func() (resp *github.Response, err error) {
+		tree, resp, err = s.client.Git.GetTree(ctx, username, repoName, "HEAD", true)
+		return resp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository tree: %w", err)
 	}
+	return tree, nil
+}
 
-	return commitDetails, nil
+// getFileContent retrieves and decodes the content of a repository file
+func (s *GitHubService) getFileContent(ctx context.Context, username, repoName, filePath string) (string, error) {
+	var content *github.RepositoryContent
+	err := s.callWithRetry(ctx, repoName, "file contents", func() (resp *github.Response, err error) {
+		content, _, resp, err = s.client.Repositories.GetContents(ctx, username, repoName, filePath, nil)
+		return resp, err
+	})
+	if err != nil {
+		return "", err
+	}
+	if content == nil || content.Content == nil {
+		return "", fmt.Errorf("no content for %s", filePath)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(*content.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", filePath, err)
+	}
+	return string(decoded), nil
 }
 
-// GetRepositoryContents retrieves repository file contents for analysis
-func (s *GitHubService) GetRepositoryContents(ctx context.Context, username, repoName string) ([]*dto.FileAnalysis, error) {
-	// Get repository tree
-	tree, _, err := s.client.Git.GetTree(ctx, username, repoName, "HEAD", true)
+// fetchFiles reads the repository files not yet in files into it. Files that can't be read are
+// stored empty so they aren't requested again.
+func (s *GitHubService) fetchFiles(ctx context.Context, username, repoName string, paths []string, files map[string]string) {
+	var missing []string
+	for _, filePath := range paths {
+		if _, ok := files[filePath]; !ok && !slices.Contains(missing, filePath) {
+			missing = append(missing, filePath)
+		}
+	}
+
+	contents := make([]string, len(missing))
+	_ = runBounded(ctx, len(missing), s.config.FileConcurrency, func(i int) {
+		contents[i], _ = s.getFileContent(ctx, username, repoName, missing[i])
+	})
+	for i, filePath := range missing {
+		files[filePath] = contents[i]
+	}
+}
+
+// GetRepositoryContents retrieves repository file contents for analysis. Files are picked by the
+// configured sampling strategy; the user's commits rank the files they changed most often.
+func (s *GitHubService) GetRepositoryContents(ctx context.Context, username, repoName string, commits []*dto.CommitDetail) ([]*dto.FileAnalysis, error) {
+	tree, err := s.getTree(ctx, username, repoName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository tree: %w", err)
+		return nil, err
 	}
+	return s.sampleFiles(ctx, username, repoName, tree, commits)
+}
 
-	// Filter and sample files
-	var codeFiles []*github.TreeEntry
+// sampleFiles samples code files from a repository tree and analyzes their contents
+func (s *GitHubService) sampleFiles(ctx context.Context, username, repoName string, tree *github.Tree, commits []*dto.CommitDetail) ([]*dto.FileAnalysis, error) {
+	// Filter and sample files, skipping vendored, generated and fixture files
+	var codeFiles, scripts []*github.TreeEntry
 	for _, entry := range tree.Entries {
-		if entry.GetType() == "blob" && s.isCodeFile(entry.GetPath()) {
+		if entry.GetType() != "blob" || !hasSampleSize(entry) {
+			continue
+		}
+		if s.isSampleCandidate(entry.GetPath()) {
 			codeFiles = append(codeFiles, entry)
+		} else if s.isScriptCandidate(entry.GetPath()) {
+			scripts = append(scripts, entry)
 		}
 	}
+	fetched := make(map[string]string)
+	codeFiles = append(codeFiles, s.shebangScripts(ctx, username, repoName, scripts, fetched)...)
 
 	// Sample files if there are too many
+	sampleCount := s.config.SampleFileCount
+	if s.config.EstimateOwnership {
+		// Estimate ownership of extra candidates so files written by others can be skipped
+		sampleCount *= ownershipCandidateFactor
+	}
 	if len(codeFiles) > s.config.SampleFileCount {
-		rand.Seed(int64(s.config.RandomSeed))
-		rand.Shuffle(len(codeFiles), func(i, j int) {
-			codeFiles[i], codeFiles[j] = codeFiles[j], codeFiles[i]
-		})
-		codeFiles = codeFiles[:s.config.SampleFileCount]
+		s.orderSampleCandidates(codeFiles, commits)
 	}
+	codeFiles = codeFiles[:min(len(codeFiles), sampleCount)]
 
-	var fileAnalyses []*dto.FileAnalysis
-	for _, file := range codeFiles {
-		analysis := s.analyzeFile(ctx, username, repoName, file)
-		if analysis != nil {
-			fileAnalyses = append(fileAnalyses, analysis)
+	ownership := make([]fileOwnership, len(codeFiles))
+	if s.config.EstimateOwnership && len(codeFiles) > 0 {
+		paths := make([]string, len(codeFiles))
+		for i, file := range codeFiles {
+			paths[i] = file.GetPath()
 		}
+		ownership = s.estimateOwnership(ctx, username, repoName, paths)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		preferOwnedFiles(codeFiles, ownership)
+		codeFiles = codeFiles[:min(len(codeFiles), s.config.SampleFileCount)]
 	}
 
-	return fileAnalyses, nil
-}
+	// Fetch file contents concurrently, keeping the sampled order
+	analyses := make([]*dto.FileAnalysis, len(codeFiles))
+	var sampled int32
+	err := runBounded(ctx, len(codeFiles), s.config.FileConcurrency, func(i int) {
+		analyses[i] = s.analyzeFile(ctx, username, repoName, codeFiles[i], fetched)
+		if analyses[i] != nil {
+			applyOwnership(analyses[i], ownership[i])
+		}
 
-// isCodeFile determines if a file is a code file worth analyzing
-func (s *GitHubService) isCodeFile(path string) bool {
-	codeExtensions := map[string]bool{
-		".go":   true, ".py":   true, ".js":   true, ".ts":   true,
-		".java": true, ".cpp":  true, ".c":    true, ".cs":   true,
-		".rb":   true, ".php":  true, ".rs":   true, ".kt":   true,
-		".swift": true, ".scala": true, ".r":    true, ".m":    true,
-		".h":    true, ".hpp":  true, ".cc":   true, ".cxx":  true,
+		done := int(atomic.AddInt32(&sampled, 1))
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressFiles,
+			Message: fmt.Sprintf("%s: sampled file %d of %d (%s)", repoName, done, len(codeFiles), codeFiles[i].GetPath()),
+			Repo:    repoName,
+			Count:   done,
+			Total:   len(codeFiles),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Get file extension
-	parts := strings.Split(path, ".")
-	if len(parts) < 2 {
-		return false
+	var fileAnalyses []*dto.FileAnalysis
+	for _, analysis := range analyses {
+		if analysis != nil {
+			fileAnalyses = append(fileAnalyses, analysis)
+		}
 	}
-	ext := "." + strings.ToLower(parts[len(parts)-1])
 
-	return codeExtensions[ext]
+	return fileAnalyses, nil
 }
 
-// analyzeFile analyzes a single file
-func (s *GitHubService) analyzeFile(ctx context.Context, username, repoName string, file *github.TreeEntry) *dto.FileAnalysis {
-	// Get file content
-	content, _, _, err := s.client.Repositories.GetContents(ctx, username, repoName, file.GetPath(), nil)
-	if err != nil {
-		return nil
-	}
-
-	if content.Content == nil {
-		return nil
+// analyzeFile analyzes a single file, reading it unless its content was already fetched
+func (s *GitHubService) analyzeFile(ctx context.Context, username, repoName string, file *github.TreeEntry, fetched map[string]string) *dto.FileAnalysis {
+	fileContent, ok := fetched[file.GetPath()]
+	if !ok {
+		var err error
+		if fileContent, err = s.getFileContent(ctx, username, repoName, file.GetPath()); err != nil {
+			return nil
+		}
 	}
 
-	// Decode content
-	decoded, err := base64.StdEncoding.DecodeString(*content.Content)
-	if err != nil {
+	language := s.languages.Detect(file.GetPath(), fileContent)
+	if s.languages.IsGenerated(file.GetPath(), fileContent) {
 		return nil
 	}
-
-	fileContent := string(decoded)
 	lines := strings.Split(fileContent, "\n")
 
 	analysis := &dto.FileAnalysis{
 		Repo:     repoName,
 		Path:     file.GetPath(),
-		Language: s.detectLanguage(file.GetPath()),
-		Size:     len(decoded),
+		Language: language,
+		Size:     len(fileContent),
 		Lines:    len(lines),
-		HasTests: s.hasTestIndicators(fileContent),
+		HasTests: s.languages.IsTest(file.GetPath()) || s.languages.HasTestCode(file.GetPath(), fileContent),
 		Content:  fileContent,
+		Metrics:  metrics.Analyze(language, fileContent),
 	}
 
 	return analysis
 }
 
-// detectLanguage detects programming language from file extension
-func (s *GitHubService) detectLanguage(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-	languages := map[string]string{
-		".go":    "Go",
-		".py":    "Python",
-		".js":    "JavaScript",
-		".ts":    "TypeScript",
-		".java":  "Java",
-		".cpp":   "C++",
-		".c":     "C",
-		".cs":    "C#",
-		".rb":    "Ruby",
-		".php":   "PHP",
-		".rs":    "Rust",
-		".kt":    "Kotlin",
-		".swift": "Swift",
-		".scala": "Scala",
-		".r":     "R",
-		".m":     "Objective-C",
-		".h":     "C/C++ Header",
-		".hpp":   "C++ Header",
-	}
-
-	if lang, exists := languages[ext]; exists {
-		return lang
-	}
-	return "Unknown"
+// errEmptyRepository is returned when a repository has no commits at all
+var errEmptyRepository = errors.New("repository is empty")
+
+// repoAnalysis holds the data collected for a single repository
+type repoAnalysis struct {
+	commits []*dto.CommitDetail
+	files   []*dto.FileAnalysis
+	diffs   []*dto.DiffSample
+	outcome dto.RepoOutcome
 }
 
-// hasTestIndicators checks if file contains test-related code
-func (s *GitHubService) hasTestIndicators(content string) bool {
-	testIndicators := []string{
-		"test", "Test", "TEST",
-		"assert", "Assert",
-		"expect", "Expect",
-		"should", "Should",
-		"describe", "it(",
-		"@Test", "unittest",
-		"testing.T", "t.Run",
-	}
-
-	contentLower := strings.ToLower(content)
-	for _, indicator := range testIndicators {
-		if strings.Contains(contentLower, strings.ToLower(indicator)) {
-			return true
-		}
+// failedOutcome records a repository whose analysis failed at the given stage
+func failedOutcome(repoName, stage string, err error) dto.RepoOutcome {
+	return dto.RepoOutcome{
+		Repo:       repoName,
+		Status:     dto.RepoError,
+		Stage:      stage,
+		Error:      err.Error(),
+		HTTPStatus: httpStatus(err),
 	}
-	return false
 }
 
-// assessCodeQuality provides a simple code quality assessment
-func (s *GitHubService) assessCodeQuality(content string) string {
-	lines := strings.Split(content, "\n")
-	
-	// Simple metrics
-	commentLines := 0
-	emptyLines := 0
-	longLines := 0
-	
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			emptyLines++
-		} else if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "/*") {
-			commentLines++
-		}
-		if len(line) > 120 {
-			longLines++
-		}
+// httpStatus returns the HTTP status code of a failed GitHub API call, or 0 if there was no response
+func httpStatus(err error) int {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) && rateErr.Response != nil {
+		return rateErr.Response.StatusCode
 	}
-	
-	totalLines := len(lines)
-	if totalLines == 0 {
-		return "Unknown"
-	}
-	
-	commentRatio := float64(commentLines) / float64(totalLines)
-	longLineRatio := float64(longLines) / float64(totalLines)
-	
-	if commentRatio > 0.2 && longLineRatio < 0.1 {
-		return "Good"
-	} else if commentRatio > 0.1 && longLineRatio < 0.2 {
-		return "Fair"
-	}
-	return "Needs Improvement"
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.Response != nil {
+		return abuseErr.Response.StatusCode
+	}
+	return 0
 }
 
-// assessComplexity provides a simple complexity assessment
-func (s *GitHubService) assessComplexity(content string) string {
-	// Count control flow statements
-	complexityKeywords := []string{
-		"if", "else", "for", "while", "switch", "case",
-		"try", "catch", "finally", "throw",
-	}
-	
-	contentLower := strings.ToLower(content)
-	complexityCount := 0
-	
-	for _, keyword := range complexityKeywords {
-		complexityCount += strings.Count(contentLower, keyword)
-	}
-	
-	lines := len(strings.Split(content, "\n"))
-	if lines == 0 {
-		return "Unknown"
-	}
-	
-	complexityRatio := float64(complexityCount) / float64(lines)
-	
-	if complexityRatio > 0.1 {
-		return "High"
-	} else if complexityRatio > 0.05 {
-		return "Medium"
-	}
-	return "Low"
+// collectRepository collects the languages of a repository and the commits GitHub linked to the
+// user. The outcome is set if the repository is empty or its commits can't be listed.
+func (s *GitHubService) collectRepository(ctx context.Context, username string, repo *dto.Repository) repoAnalysis {
+	analysis := repoAnalysis{outcome: dto.RepoOutcome{Repo: repo.Name}}
+
+	// Get repository languages
+	languages, err := s.collector.GetRepositoryLanguages(ctx, username, repo.Name)
+	if err == nil {
+		repo.LanguagesUsed = languages
+	}
+
+	// Get commits
+	commits, err := s.collector.GetRepositoryCommits(ctx, username, repo.Name, s.config.CommitsPerRepo)
+	if errors.Is(err, errEmptyRepository) {
+		analysis.outcome.Status = dto.RepoSkippedEmpty
+		return analysis
+	}
+	if err != nil {
+		analysis.outcome = failedOutcome(repo.Name, "commits", err)
+		return analysis
+	}
+	analysis.commits = commits
+	return analysis
+}
+
+// analyzeRepository completes the analysis of a collected repository with the commits made with
+// the user's other identities, its tests, technologies and sampled files. Commits are kept even
+// if file sampling fails afterwards.
+func (s *GitHubService) analyzeRepository(ctx context.Context, username string, repo *dto.Repository, analysis repoAnalysis, ids authorIdentities) repoAnalysis {
+	// Attribute commits made with the user's emails or names that aren't linked to the account
+	commits := s.addIdentityCommits(ctx, username, repo.Name, analysis.commits, s.config.CommitsPerRepo, ids)
+
+	if len(commits) == 0 {
+		analysis.outcome.Status = dto.RepoSkippedNoCommits
+		return analysis
+	}
+
+	analysis.commits = commits
+	repo.CommitCount = len(commits)
+	repo.Activity = metrics.Activity(commits)
+	repo.CommitMessages = metrics.Messages(commits)
+
+	// Get the file tree, used to detect tests and to sample files
+	tree, treeErr := s.getTree(ctx, username, repo.Name)
+	if treeErr == nil {
+		// Manifests are read for both test frameworks and technologies; share their contents
+		files := make(map[string]string)
+		repo.Tests = s.detectTests(ctx, username, repo.Name, tree, files)
+		repo.Technologies = s.detectTechnologies(ctx, username, repo.Name, tree, files)
+	}
+
+	// Sample the user's own changes instead of whole files
+	if s.config.SampleSource == config.SampleSourceDiffs {
+		analysis.diffs = sampleCommitDiffs(commits, s.config.DiffBudgetChars)
+		analysis.outcome.Status = dto.RepoAnalyzed
+		repo.FileCount = len(analysis.diffs)
+		repo.IncludeAnalysis = true
+		return analysis
+	}
+
+	// Get file analysis
+	if treeErr != nil {
+		analysis.outcome = failedOutcome(repo.Name, "files", treeErr)
+		return analysis
+	}
+	fileAnalyses, err := s.sampleFiles(ctx, username, repo.Name, tree, commits)
+	if err != nil {
+		analysis.outcome = failedOutcome(repo.Name, "files", err)
+		return analysis
+	}
+
+	fileMetrics := make([]*dto.FileMetrics, len(fileAnalyses))
+	for i, file := range fileAnalyses {
+		fileMetrics[i] = file.Metrics
+	}
+
+	analysis.files = fileAnalyses
+	analysis.outcome.Status = dto.RepoAnalyzed
+	repo.FileCount = len(fileAnalyses)
+	repo.Metrics = metrics.Summarize(fileMetrics)
+	repo.IncludeAnalysis = true
+	return analysis
 }
 
 // PerformFullAudit performs a comprehensive GitHub user audit
 func (s *GitHubService) PerformFullAudit(ctx context.Context, username string) (*dto.AuditResult, error) {
+	s.resetAPIUsage()
+	s.collector.reset()
+
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressUser,
+		Message:  fmt.Sprintf("Fetching user information for %s...", username),
+		Fraction: 0.02,
+	})
+
 	// Get user information
-	userInfo, err := s.GetUser(ctx, username)
+	userInfo, err := s.collector.GetUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 
 	// Get repositories
-	repositories, err := s.ListRepositories(ctx, username)
+	s.reportProgress(ProgressEvent{
+		Kind:     ProgressRepositories,
+		Message:  "Listing repositories...",
+		Fraction: 0.05,
+	})
+	privateIncluded := s.includePrivateRepos(ctx, username)
+	if s.config.IncludePrivateRepo && !privateIncluded {
+		s.reportProgress(ProgressEvent{
+			Kind:     ProgressRepositories,
+			Message:  "Private repositories are only listed when the token belongs to the audited user, using public repositories",
+			Fraction: 0.05,
+		})
+	}
+
+	repositories, err := s.collector.ListRepositories(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repositories: %w", err)
 	}
 
-	// Filter repositories for analysis (recent activity)
 	cutoffDate := time.Now().AddDate(-s.config.AnalysisYears, 0, 0)
+	countFailures, err := s.countUserCommits(ctx, username, s.commitCountCandidates(repositories, cutoffDate))
+	if err != nil {
+		return nil, err
+	}
+
+	activity := s.collectActivity(ctx, username)
+	activity.errors = append(countFailures, activity.errors...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Filter repositories for analysis (recent activity)
 	var analysisRepos []*dto.Repository
 	var originalRepos []*dto.Repository
 	var forkedRepos []*dto.Repository
-	
+
 	for _, repo := range repositories {
 		if repo.Fork {
 			forkedRepos = append(forkedRepos, repo)
 		} else {
 			originalRepos = append(originalRepos, repo)
 		}
-		
-		if repo.UpdatedAt.After(cutoffDate) {
+
+		if s.isAnalysisCandidate(repo, cutoffDate) {
 			analysisRepos = append(analysisRepos, repo)
 		}
 	}
 
-	// Sample repositories for detailed analysis - select N most recently updated
+	// Sample repositories for detailed analysis - select N most recently pushed
 	if len(analysisRepos) > s.config.SampledRepoCount {
-		// Sort repositories by UpdatedAt in descending order (most recent first)
+		// Sort repositories by last push in descending order (most recent first)
 		sort.Slice(analysisRepos, func(i, j int) bool {
-			return analysisRepos[i].UpdatedAt.After(analysisRepos[j].UpdatedAt)
+			return lastPush(analysisRepos[i]).After(lastPush(analysisRepos[j]))
 		})
-		// Take the N most recently updated repositories
+		// Take the N most recently pushed repositories
 		analysisRepos = analysisRepos[:s.config.SampledRepoCount]
 	}
 
+	repoNames := make([]string, len(analysisRepos))
+	for i, repo := range analysisRepos {
+		repoNames[i] = repo.Name
+	}
+	s.setProgressTracker(newAuditProgress(repoNames))
+	defer s.setProgressTracker(nil)
+
+	s.reportProgress(ProgressEvent{
+		Kind:    ProgressRepositories,
+		Message: fmt.Sprintf("Found %d repositories, analyzing %d", len(repositories), len(analysisRepos)),
+		Count:   len(analysisRepos),
+		Total:   len(repositories),
+	})
+
+	// Let batching collectors fetch the commit history of all selected repositories up front
+	if err := s.collector.prefetchCommits(ctx, username, analysisRepos, s.config.CommitsPerRepo); err != nil {
+		return nil, err
+	}
+
 	// Analyze repositories in detail
 	var allCommits []*dto.CommitDetail
 	var allFileAnalyses []*dto.FileAnalysis
+	var allDiffSamples []*dto.DiffSample
 	var analyzedRepos []string
+	var inventoried []*dto.Repository // Analyzed repositories, for the technology inventory
 	var reposWithErrors []string
+	var reposWithoutUserCommits []string
+	var reposWithTests, reposTestedInCI int
+	outcomes := make([]dto.RepoOutcome, 0, len(analysisRepos))
+
+	// Analyze repositories concurrently; results are merged in the sampled order below
+	analyses := make([]repoAnalysis, len(analysisRepos))
+	err = runBounded(ctx, len(analysisRepos), s.config.RepoConcurrency, func(i int) {
+		repo := analysisRepos[i]
+
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressRepoStarted,
+			Message: fmt.Sprintf("Analyzing repository %d of %d: %s", i+1, len(analysisRepos), repo.Name),
+			Repo:    repo.Name,
+		})
 
-	for _, repo := range analysisRepos {
-		// Get repository languages
-		languages, err := s.GetRepositoryLanguages(ctx, username, repo.Name)
-		if err == nil {
-			repo.LanguagesUsed = languages
-		}
+		analyses[i] = s.collectRepository(ctx, username, repo)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// Get commits
-		commits, err := s.GetRepositoryCommits(ctx, username, repo.Name, s.config.CommitsPerRepo)
-		if err != nil {
-			reposWithErrors = append(reposWithErrors, repo.Name)
-			continue
-		}
-		
-		if len(commits) == 0 {
-			continue // Skip repos without user commits
+	// The user's emails come from the linked commits of every repository, so commits made with
+	// one are attributed even in repositories without linked commits
+	var linked []*dto.CommitDetail
+	for _, analysis := range analyses {
+		linked = append(linked, analysis.commits...)
+	}
+	ids := s.userIdentities(username, linked)
+
+	err = runBounded(ctx, len(analysisRepos), s.config.RepoConcurrency, func(i int) {
+		repo := analysisRepos[i]
+
+		if analyses[i].outcome.Status == "" {
+			analyses[i] = s.analyzeRepository(ctx, username, repo, analyses[i], ids)
 		}
 
-		allCommits = append(allCommits, commits...)
-		repo.CommitCount = len(commits)
+		s.reportProgress(ProgressEvent{
+			Kind:    ProgressRepoDone,
+			Message: fmt.Sprintf("Finished repository %d of %d: %s", i+1, len(analysisRepos), repo.Name),
+			Repo:    repo.Name,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// Get file analysis
-		fileAnalyses, err := s.GetRepositoryContents(ctx, username, repo.Name)
-		if err != nil {
+	for i, repo := range analysisRepos {
+		analysis := analyses[i]
+
+		allCommits = append(allCommits, analysis.commits...)
+		outcomes = append(outcomes, analysis.outcome)
+		switch analysis.outcome.Status {
+		case dto.RepoError:
 			reposWithErrors = append(reposWithErrors, repo.Name)
 			continue
+		case dto.RepoSkippedNoCommits, dto.RepoSkippedEmpty:
+			reposWithoutUserCommits = append(reposWithoutUserCommits, repo.Name)
+			continue
 		}
 
-		allFileAnalyses = append(allFileAnalyses, fileAnalyses...)
-		repo.FileCount = len(fileAnalyses)
-		repo.IncludeAnalysis = true
+		allFileAnalyses = append(allFileAnalyses, analysis.files...)
+		allDiffSamples = append(allDiffSamples, analysis.diffs...)
 		analyzedRepos = append(analyzedRepos, repo.Name)
+		inventoried = append(inventoried, repo)
+		if repo.Tests != nil && repo.Tests.TestFiles > 0 {
+			reposWithTests++
+		}
+		if repo.Tests != nil && repo.Tests.CIRunsTests {
+			reposTestedInCI++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Calculate statistics
 	totalStars := 0
 	significantForks := 0
+	privateRepos := 0
+	archivedRepos := 0
 	for _, repo := range repositories {
 		totalStars += repo.Stars
-		if repo.Fork && repo.UserCommits > 10 {
+		if repo.Private {
+			privateRepos++
+		}
+		if repo.Archived {
+			archivedRepos++
+		}
+		if repo.Fork && repo.UserCommits > significantForkCommits {
 			significantForks++
 			repo.IsSignificant = true
 		}
@@ -490,30 +863,85 @@ func (s *GitHubService) PerformFullAudit(ctx context.Context, username string) (
 		UserInfo: *userInfo,
 		RepoStats: dto.RepoStats{
 			Statistics: dto.RepoStatistics{
-				TotalRepos:       len(repositories),
-				OriginalRepos:    len(originalRepos),
-				ForkedRepos:      len(forkedRepos),
-				SignificantForks: significantForks,
-				TotalStars:       totalStars,
-				AnalysisRepos:    len(analysisRepos),
+				TotalRepos:           len(repositories),
+				OriginalRepos:        len(originalRepos),
+				ForkedRepos:          len(forkedRepos),
+				SignificantForks:     significantForks,
+				PrivateRepos:         privateRepos,
+				ArchivedRepos:        archivedRepos,
+				PrivateReposIncluded: privateIncluded,
+				TotalStars:           totalStars,
+				AnalysisRepos:        len(analysisRepos),
+				ReposWithTests:       reposWithTests,
+				ReposTestedInCI:      reposTestedInCI,
 			},
 			OriginalRepos: originalRepos,
 			ForkedRepos:   forkedRepos,
 		},
-		FileAnalysis:  allFileAnalyses,
-		CommitDetails: allCommits,
-		AuditParameters: s.getSanitizedConfig(),
+		FileAnalysis:          allFileAnalyses,
+		CommitDetails:         allCommits,
+		CommitActivity:        metrics.Activity(allCommits),
+		CommitMessages:        metrics.Messages(allCommits),
+		Technologies:          manifests.Combine(inventoried),
+		DiffSamples:           allDiffSamples,
+		PullRequests:          activity.pullRequests,
+		CodeReviews:           activity.codeReviews,
+		IssueActivity:         activity.issues,
+		ExternalContributions: activity.external,
+		AuditParameters:       s.getSanitizedConfig(),
 		AnalysisSummary: dto.AnalysisSummary{
-			ReposAnalyzedForCode:     analyzedRepos,
-			ReposWithErrors:          reposWithErrors,
-			TotalReposAttempted:      len(analysisRepos),
-			SuccessfulAnalysisCount:  len(analyzedRepos),
+			ReposAnalyzedForCode:    analyzedRepos,
+			ReposWithErrors:         reposWithErrors,
+			ReposWithoutUserCommits: reposWithoutUserCommits,
+			TotalReposAttempted:     len(analysisRepos),
+			SuccessfulAnalysisCount: len(analyzedRepos),
+			RepoOutcomes:            outcomes,
+			CollectionErrors:        activity.errors,
+			SampledLanguages:        sampledLanguages(allFileAnalyses, allDiffSamples),
+			APIUsage:                s.APIUsage(),
 		},
 	}
 
 	return result, nil
 }
 
+// isAnalysisCandidate reports whether a repository may be selected for analysis: it can be read,
+// isn't an archived repository left out by the configuration and was pushed after the cutoff
+func (s *GitHubService) isAnalysisCandidate(repo *dto.Repository, cutoff time.Time) bool {
+	switch {
+	case repo.Disabled:
+		// Disabled repositories can't be read
+		return false
+	case repo.Archived && !s.config.IncludeArchived:
+		return false
+	}
+	return lastPush(repo).After(cutoff)
+}
+
+// lastPush returns when a repository last received a push. UpdatedAt, which also changes when the
+// repository is starred, is only used when the push time is unknown.
+func lastPush(repo *dto.Repository) time.Time {
+	if repo.PushedAt.IsZero() {
+		return repo.UpdatedAt
+	}
+	return repo.PushedAt
+}
+
+// sampledLanguages counts the sampled files and diff hunks per detected language
+func sampledLanguages(files []*dto.FileAnalysis, diffs []*dto.DiffSample) map[string]int {
+	if len(files) == 0 && len(diffs) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, file := range files {
+		counts[file.Language]++
+	}
+	for _, diff := range diffs {
+		counts[diff.Language]++
+	}
+	return counts
+}
+
 // getSanitizedConfig returns a copy of the config with sensitive information removed
 func (s *GitHubService) getSanitizedConfig() config.GitHubConfig {
 	// Create a copy of the config without the token
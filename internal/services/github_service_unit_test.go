@@ -1,8 +1,14 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+
 	"dev_profiler/internal/config"
+	"dev_profiler/internal/dto"
 )
 
 func TestNewGitHubService(t *testing.T) {
@@ -44,270 +50,421 @@ func TestNewGitHubServiceWithoutToken(t *testing.T) {
 	}
 }
 
-func TestIsCodeFile(t *testing.T) {
-	cfg := &config.GitHubConfig{}
+func TestGetSanitizedConfig(t *testing.T) {
+	cfg := &config.GitHubConfig{
+		Token: "secret-token-123",
+		SampledRepoCount: 10,
+	}
 	service := NewGitHubService(cfg)
 	
-	testCases := []struct {
-		path     string
-		expected bool
-	}{
-		// Code files (based on actual implementation)
-		{"main.go", true},
-		{"script.py", true},
-		{"app.js", true},
-		{"app.ts", true},
-		{"service.java", true},
-		{"utils.cpp", true},
-		{"main.c", true},
-		{"app.cs", true},
-		{"model.rb", true},
-		{"controller.php", true},
-		{"main.rs", true},
-		{"app.kt", true},
-		{"app.swift", true},
-		{"app.scala", true},
-		{"script.r", true},
-		{"main.m", true},
-		{"header.h", true},
-		{"header.hpp", true},
-		{"utils.cc", true},
-		{"main.cxx", true},
-		
-		// Non-code files
-		{"README.md", false},
-		{"LICENSE", false},
-		{"image.png", false},
-		{"document.pdf", false},
-		{"data.json", false},
-		{"config.ini", false},
-		{"log.txt", false},
-		{".gitignore", false},
-		{".env", false},
-		{"CHANGELOG.md", false},
-		{"style.css", false},
-		{"index.html", false},
-		{"component.tsx", false},
-		{"config.yml", false},
-		{"docker-compose.yaml", false},
-		{"Dockerfile", false},
-		{"Makefile", false},
-		{"package.json", false},
-		{"requirements.txt", false},
-		{"pom.xml", false},
-		{"build.gradle", false},
-		
-		// Edge cases
-		{"", false},
-		{"file_without_extension", false},
-		{".hidden", false},
+	sanitized := service.getSanitizedConfig()
+	
+	if sanitized.Token != "[REDACTED]" {
+		t.Errorf("Token should be sanitized, got %q", sanitized.Token)
 	}
 	
-	for _, tc := range testCases {
-		t.Run(tc.path, func(t *testing.T) {
-			result := service.isCodeFile(tc.path)
-			if result != tc.expected {
-				t.Errorf("isCodeFile(%q) = %v, expected %v", tc.path, result, tc.expected)
-			}
-		})
+	if sanitized.SampledRepoCount != 10 {
+		t.Errorf("SampledRepoCount should not be sanitized, got %d", sanitized.SampledRepoCount)
 	}
 }
 
-func TestDetectLanguage(t *testing.T) {
-	cfg := &config.GitHubConfig{}
-	service := NewGitHubService(cfg)
-	
-	testCases := []struct {
-		path     string
-		expected string
-	}{
-		// Supported languages
-		{"main.go", "Go"},
-		{"script.py", "Python"},
-		{"app.js", "JavaScript"},
-		{"app.ts", "TypeScript"},
-		{"service.java", "Java"},
-		{"utils.cpp", "C++"},
-		{"main.c", "C"},
-		{"app.cs", "C#"},
-		{"model.rb", "Ruby"},
-		{"controller.php", "PHP"},
-		{"main.rs", "Rust"},
-		{"app.kt", "Kotlin"},
-		{"app.swift", "Swift"},
-		{"app.scala", "Scala"},
-		{"script.r", "R"},
-		{"main.m", "Objective-C"},
-		{"header.h", "C/C++ Header"},
-		{"header.hpp", "C++ Header"},
-		
-		// Unsupported extensions (should return "Unknown")
-		{"component.tsx", "Unknown"},
-		{"style.css", "Unknown"},
-		{"index.html", "Unknown"},
-		{"script.sh", "Unknown"},
-		{"config.yml", "Unknown"},
-		{"docker-compose.yaml", "Unknown"},
-		{"Dockerfile", "Unknown"},
-		{"Makefile", "Unknown"},
-		{"package.json", "Unknown"},
-		{"data.xml", "Unknown"},
-		{"query.sql", "Unknown"},
-		{"style.scss", "Unknown"},
-		{"style.less", "Unknown"},
-		{"config.toml", "Unknown"},
-		{"notebook.ipynb", "Unknown"},
-		{"unknown.xyz", "Unknown"},
-		{"file_without_extension", "Unknown"},
-		{"", "Unknown"},
+func TestPerformFullAuditCancelled(t *testing.T) {
+	service := NewGitHubService(config.DefaultGitHubConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := service.PerformFullAudit(ctx, "octocat")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
 	}
-	
-	for _, tc := range testCases {
-		t.Run(tc.path, func(t *testing.T) {
-			result := service.detectLanguage(tc.path)
-			if result != tc.expected {
-				t.Errorf("detectLanguage(%q) = %q, expected %q", tc.path, result, tc.expected)
-			}
-		})
+	if result != nil {
+		t.Error("No result should be returned for a cancelled audit")
 	}
 }
 
-func TestHasTestIndicators(t *testing.T) {
-	cfg := &config.GitHubConfig{}
-	service := NewGitHubService(cfg)
-	
+func TestPerformFullAuditDeterministicWithConcurrency(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "alpha", commits: 6, files: []string{"a.go", "b.go", "c.go", "d.go", "e.go"}},
+		{name: "beta", commits: 3, files: []string{"main.go", "util.go", "README.md"}},
+		{name: "gamma", commits: 0, files: []string{"x.go"}},
+		{name: "delta", fork: true, commits: 4, files: []string{"p.py", "q.py", "r.py", "s.py"}},
+	}
+	server := newFakeGitHubServer(t, "octocat", repos)
+
+	audit := func(concurrency int) string {
+		cfg := config.DefaultGitHubConfig()
+		cfg.SampleFileCount = 2
+		cfg.RepoConcurrency = concurrency
+		cfg.CommitConcurrency = concurrency
+		cfg.FileConcurrency = concurrency
+
+		result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+		if err != nil {
+			t.Fatalf("PerformFullAudit() failed: %v", err)
+		}
+
+		var b strings.Builder
+		for _, commit := range result.CommitDetails {
+			fmt.Fprintf(&b, "commit %s %v\n", commit.SHA, commit.FilesChanged)
+		}
+		for _, file := range result.FileAnalysis {
+			fmt.Fprintf(&b, "file %s/%s\n", file.Repo, file.Path)
+		}
+		fmt.Fprintf(&b, "analyzed %v\n", result.AnalysisSummary.ReposAnalyzedForCode)
+		return b.String()
+	}
+
+	serial := audit(1)
+	if !strings.Contains(serial, "commit alpha-0 [alpha-0.go]") {
+		t.Fatalf("Serial audit is missing commit details:\n%s", serial)
+	}
+	if !strings.Contains(serial, "analyzed [alpha beta delta]") {
+		t.Errorf("Unexpected analyzed repositories:\n%s", serial)
+	}
+
+	for i := 0; i < 3; i++ {
+		if concurrent := audit(8); concurrent != serial {
+			t.Errorf("Concurrent audit differs from serial audit.\nSerial:\n%s\nConcurrent:\n%s", serial, concurrent)
+		}
+	}
+}
+
+func TestPerformFullAuditPrivateRepos(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "public", commits: 1, files: []string{"a.go"}},
+		{name: "secret", private: true, commits: 1, files: []string{"b.go"}},
+		{name: "shared", collaborator: true, commits: 1, files: []string{"c.go"}},
+	}
+
 	testCases := []struct {
-		name     string
-		content  string
-		expected bool
+		name            string
+		backend         string
+		includePrivate  bool
+		ownedOnly       bool
+		viewer          string
+		expectedPrivate int
+		expectedTotal   int
 	}{
-		{"contains test import", "import testing", true},
-		{"contains unittest", "import unittest", true},
-		{"contains pytest", "import pytest", true},
-		{"contains jest", "const jest = require('jest'); describe('test', () => {})", true},
-		{"contains mocha", "describe('test', function() {", true},
-		{"contains go testing", "func TestSomething(t *testing.T) {", true},
-		{"contains test function", "def test_something():", true},
-		{"contains describe block", "describe('component', () => {", true},
-		{"contains it block", "it('should work', () => {", true},
-		{"contains expect assertion", "expect(result).toBe(true)", true},
-		{"contains assert", "assert result == expected", true},
-		{"no test indicators", "func main() { fmt.Println('hello') }", false},
-		{"empty content", "", false},
-		{"only comments", "// This is a comment\n/* Another comment */", false},
+		{"token owner with REST", config.CollectorREST, true, false, "octocat", 1, 3},
+		{"token owner with GraphQL", config.CollectorGraphQL, true, false, "octocat", 1, 3},
+		{"token owner's own repos with REST", config.CollectorREST, true, true, "octocat", 1, 2},
+		{"token owner's own repos with GraphQL", config.CollectorGraphQL, true, true, "octocat", 1, 2},
+		{"other token owner", config.CollectorREST, true, false, "someone-else", 0, 2},
+		{"other token owner with GraphQL", config.CollectorGraphQL, true, false, "someone-else", 0, 2},
+		{"other token owner's own repos", config.CollectorREST, true, true, "someone-else", 0, 1},
+		{"other token owner's own repos with GraphQL", config.CollectorGraphQL, true, true, "someone-else", 0, 1},
+		{"private repos disabled", config.CollectorREST, false, false, "octocat", 0, 2},
 	}
-	
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := service.hasTestIndicators(tc.content)
-			if result != tc.expected {
-				t.Errorf("hasTestIndicators() = %v, expected %v", result, tc.expected)
+			server := newFakeGitHubServer(t, "octocat", repos)
+			server.viewer = tc.viewer
+
+			cfg := config.DefaultGitHubConfig()
+			cfg.Token = "test-token"
+			cfg.CollectorBackend = tc.backend
+			cfg.IncludePrivateRepo = tc.includePrivate
+			cfg.OwnedReposOnly = tc.ownedOnly
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			stats := result.RepoStats.Statistics
+			if stats.PrivateRepos != tc.expectedPrivate {
+				t.Errorf("Expected %d private repos, got %d", tc.expectedPrivate, stats.PrivateRepos)
+			}
+			if stats.TotalRepos != tc.expectedTotal {
+				t.Errorf("Expected %d repos, got %d", tc.expectedTotal, stats.TotalRepos)
+			}
+			if stats.PrivateReposIncluded != (tc.expectedPrivate > 0) {
+				t.Errorf("Unexpected PrivateReposIncluded: %v", stats.PrivateReposIncluded)
+			}
+			for _, repo := range result.RepoStats.OriginalRepos {
+				if repo.Private != (repo.Name == "secret") {
+					t.Errorf("Repository %s has Private=%v", repo.Name, repo.Private)
+				}
 			}
 		})
 	}
 }
 
-func TestAssessCodeQuality(t *testing.T) {
-	cfg := &config.GitHubConfig{}
-	service := NewGitHubService(cfg)
-	
-	testCases := []struct {
-		name     string
-		content  string
-		expected string
-	}{
-		{
-			name:     "high quality code",
-			content:  "// Well documented function\n// Another comment\n// More documentation\nfunc calculateSum(a, b int) int {\n    return a + b\n}\n\n// Another documented function\nfunc multiply(x, y int) int {\n    return x * y\n}",
-			expected: "Good",
-		},
-		{
-			name:     "medium quality code",
-			content:  "func calculate(a, b int) int {\n    return a + b\n}\n\n// some processing\nfunc process() {\n    doSomething()\n}",
-			expected: "Fair",
-		},
-		{
-			name:     "low quality code",
-			content:  "func f(x,y int)int{return x+y}\nfunc g(){}\nfunc h(){}",
-			expected: "Needs Improvement",
-		},
-		{
-			name:     "empty content",
-			content:  "",
-			expected: "Needs Improvement", // Fixed: actual implementation returns "Needs Improvement" for empty content
-		},
-		{
-			name:     "only whitespace",
-			content:  "   \n\n   \t  ",
-			expected: "Needs Improvement",
-		},
+func TestPerformFullAuditCountsUserCommits(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "alpha", commits: 15, files: []string{"a.go"}},
+		{name: "gamma", commits: 0},
+		{name: "delta", fork: true, commits: 20, forkCommits: 12, files: []string{"d.go"}},
+		{name: "epsilon", fork: true, commits: 30, forkCommits: 2, files: []string{"e.go"}},
 	}
-	
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := service.assessCodeQuality(tc.content)
-			if result != tc.expected {
-				t.Errorf("assessCodeQuality() = %q, expected %q", result, tc.expected)
+
+	expected := map[string]struct{ user, upstream int }{
+		"alpha":   {15, 0},
+		"gamma":   {0, 0},
+		"delta":   {12, 8},
+		"epsilon": {2, 28},
+	}
+
+	for _, backend := range []string{config.CollectorREST, config.CollectorGraphQL} {
+		t.Run(backend, func(t *testing.T) {
+			server := newFakeGitHubServer(t, "octocat", repos)
+			cfg := config.DefaultGitHubConfig()
+			cfg.Token = "test-token"
+			cfg.CollectorBackend = backend
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			all := append(append([]*dto.Repository{}, result.RepoStats.OriginalRepos...), result.RepoStats.ForkedRepos...)
+			if len(all) != len(expected) {
+				t.Fatalf("Expected %d repositories, got %d", len(expected), len(all))
+			}
+			for _, repo := range all {
+				want := expected[repo.Name]
+				if repo.UserCommits != want.user || repo.UpstreamCommits != want.upstream {
+					t.Errorf("%s: expected %d user and %d upstream commits, got %d and %d",
+						repo.Name, want.user, want.upstream, repo.UserCommits, repo.UpstreamCommits)
+				}
+				if repo.Fork && repo.ForkSource != "upstream/"+repo.Name {
+					t.Errorf("%s: unexpected fork source %q", repo.Name, repo.ForkSource)
+				}
+			}
+
+			if significant := result.RepoStats.Statistics.SignificantForks; significant != 1 {
+				t.Errorf("Expected 1 significant fork, got %d", significant)
+			}
+			if errs := result.AnalysisSummary.CollectionErrors; len(errs) != 0 {
+				t.Errorf("Unexpected collection errors: %v", errs)
 			}
 		})
 	}
 }
 
-func TestAssessComplexity(t *testing.T) {
-	cfg := &config.GitHubConfig{}
-	service := NewGitHubService(cfg)
-	
-	testCases := []struct {
-		name     string
-		content  string
-		expected string
-	}{
-		{
-			name:     "high complexity",
-			content:  "if (condition) {\n    for (let i = 0; i < 10; i++) {\n        if (i % 2 === 0) {\n            while (true) {\n                switch (value) {\n                    case 1: break;\n                }\n            }\n        }\n    }\n}",
-			expected: "High",
-		},
-		{
-			name:     "medium complexity",
-			content:  "if condition\nfor i in range(5)\n    if check\n        process()\nelse\n    handle()",
-			expected: "High", // Fixed: actual implementation returns "High" for this content
-		},
-		{
-			name:     "low complexity",
-			content:  "function add(a, b) {\n    return a + b;\n}\n\nfunction greet(name) {\n    return 'Hello ' + name;\n}",
-			expected: "Low",
-		},
-		{
-			name:     "empty content",
-			content:  "",
-			expected: "Low", // Fixed: actual implementation returns "Low" for empty content
-		},
+func TestPerformFullAuditRecordsCommitCountFailures(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 3, files: []string{"a.go"}},
+		{name: "zeta", fork: true, commits: 5, forkCommits: 2, upstreamGone: true, files: []string{"z.go"}},
+	})
+	cfg := config.DefaultGitHubConfig()
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
 	}
-	
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := service.assessComplexity(tc.content)
-			if result != tc.expected {
-				t.Errorf("assessComplexity() = %q, expected %q", result, tc.expected)
+
+	// Without the upstream comparison all of the fork's commits are attributed to it
+	for _, repo := range result.RepoStats.ForkedRepos {
+		if repo.Name == "zeta" && (repo.UserCommits != 5 || repo.UpstreamCommits != 0) {
+			t.Errorf("Expected 5 user and 0 upstream commits, got %d and %d", repo.UserCommits, repo.UpstreamCommits)
+		}
+	}
+
+	errs := result.AnalysisSummary.CollectionErrors
+	if len(errs) != 1 || !strings.Contains(errs[0], "failed to compare fork octocat/zeta") {
+		t.Errorf("Expected the failed fork comparison to be recorded, got %v", errs)
+	}
+}
+
+func TestPerformFullAuditCountsCommitsOfCandidatesOnly(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 1, files: []string{"a.go"}},
+		{name: "beta", commits: 2, files: []string{"b.go"}},
+		{name: "gamma", commits: 3, files: []string{"c.go"}},
+		{name: "delta", commits: 4, files: []string{"d.go"}},
+		{name: "stale", commits: 5, files: []string{"s.go"}, stalePush: true},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.SampledRepoCount = 1
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	// Three times the one sampled repository, the most recently pushed first
+	expected := map[string]int{"alpha": 1, "beta": 2, "gamma": 3, "delta": 0, "stale": 0}
+	for _, repo := range result.RepoStats.OriginalRepos {
+		if repo.UserCommits != expected[repo.Name] {
+			t.Errorf("%s: expected %d user commits, got %d", repo.Name, expected[repo.Name], repo.UserCommits)
+		}
+	}
+	usage := result.AnalysisSummary.APIUsage
+	if usage.CommitCountCalls != 3 || usage.Calls <= usage.CommitCountCalls {
+		t.Errorf("Expected 3 of %d calls to count commits, got %d", usage.Calls, usage.CommitCountCalls)
+	}
+}
+
+func TestPerformFullAuditRecordsRepoOutcomes(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go"}},
+		{name: "beta", commits: 0, files: []string{"b.go"}},
+		{name: "gamma", empty: true},
+		{name: "delta", commits: 1, missingTree: true},
+	}
+
+	for _, backend := range []string{config.CollectorREST, config.CollectorGraphQL} {
+		t.Run(backend, func(t *testing.T) {
+			server := newFakeGitHubServer(t, "octocat", repos)
+			cfg := config.DefaultGitHubConfig()
+			cfg.Token = "test-token"
+			cfg.CollectorBackend = backend
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			summary := result.AnalysisSummary
+			expected := []dto.RepoOutcome{
+				{Repo: "alpha", Status: dto.RepoAnalyzed},
+				{Repo: "beta", Status: dto.RepoSkippedNoCommits},
+				{Repo: "gamma", Status: dto.RepoSkippedEmpty},
+				{Repo: "delta", Status: dto.RepoError, Stage: "files", HTTPStatus: 404},
+			}
+			if len(summary.RepoOutcomes) != len(expected) {
+				t.Fatalf("Expected %d outcomes, got %+v", len(expected), summary.RepoOutcomes)
+			}
+			for i, want := range expected {
+				got := summary.RepoOutcomes[i]
+				if got.Repo != want.Repo || got.Status != want.Status || got.Stage != want.Stage || got.HTTPStatus != want.HTTPStatus {
+					t.Errorf("Outcome %d: expected %+v, got %+v", i, want, got)
+				}
+				if (want.Status == dto.RepoError) != (got.Error != "") {
+					t.Errorf("Outcome %d: unexpected error message %q", i, got.Error)
+				}
+			}
+
+			if fmt.Sprint(summary.ReposWithoutUserCommits) != "[beta gamma]" {
+				t.Errorf("Unexpected repos without user commits: %v", summary.ReposWithoutUserCommits)
+			}
+			if fmt.Sprint(summary.ReposWithErrors) != "[delta]" {
+				t.Errorf("Unexpected repos with errors: %v", summary.ReposWithErrors)
+			}
+			if fmt.Sprint(summary.ReposAnalyzedForCode) != "[alpha]" {
+				t.Errorf("Unexpected analyzed repos: %v", summary.ReposAnalyzedForCode)
 			}
 		})
 	}
 }
 
-func TestGetSanitizedConfig(t *testing.T) {
-	cfg := &config.GitHubConfig{
-		Token: "secret-token-123",
-		SampledRepoCount: 10,
+func TestPerformFullAuditAttachesMetrics(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 2, files: []string{"a.go", "b.go"}},
+	})
+
+	result, err := newFakeGitHubService(t, server, config.DefaultGitHubConfig()).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
 	}
-	service := NewGitHubService(cfg)
-	
-	sanitized := service.getSanitizedConfig()
-	
-	if sanitized.Token != "[REDACTED]" {
-		t.Errorf("Token should be sanitized, got %q", sanitized.Token)
+
+	if len(result.FileAnalysis) != 2 {
+		t.Fatalf("Expected 2 sampled files, got %d", len(result.FileAnalysis))
 	}
-	
-	if sanitized.SampledRepoCount != 10 {
-		t.Errorf("SampledRepoCount should not be sanitized, got %d", sanitized.SampledRepoCount)
+	for _, file := range result.FileAnalysis {
+		if file.Metrics == nil || file.Metrics.Method != "ast" || file.Metrics.CodeLines != 1 || file.Metrics.CommentLines != 1 {
+			t.Errorf("Unexpected metrics for %s: %+v", file.Path, file.Metrics)
+		}
+	}
+
+	repo := result.RepoStats.OriginalRepos[0]
+	if repo.Metrics == nil || repo.Metrics.Files != 2 || repo.Metrics.CodeLines != 2 || repo.Metrics.CommentRatio != 0.5 {
+		t.Errorf("Unexpected repository metrics: %+v", repo.Metrics)
+	}
+}
+
+func TestPerformFullAuditSummarizesCommitActivity(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{name: "alpha", commits: 3, files: []string{"a.go"}},
+		{name: "beta", commits: 2, files: []string{"b.go"}},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.SampledRepoCount = 2
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	// Every fake commit adds two lines
+	for _, commit := range result.CommitDetails {
+		if commit.Additions != 2 || commit.Deletions != 0 {
+			t.Errorf("Unexpected line stats for %s: +%d -%d", commit.SHA, commit.Additions, commit.Deletions)
+		}
+	}
+
+	activity := result.CommitActivity
+	if activity == nil || activity.Commits != 5 || activity.Churn != 10 || activity.MedianCommitSize != 2 || activity.CommitsPerWeek != 5 {
+		t.Fatalf("Unexpected commit activity: %+v", activity)
+	}
+	for _, repo := range result.RepoStats.OriginalRepos {
+		if repo.Activity == nil || repo.Activity.Commits != repo.CommitCount {
+			t.Errorf("Activity of %s should cover its %d commits, got %+v", repo.Name, repo.CommitCount, repo.Activity)
+		}
+	}
+}
+
+func TestPerformFullAuditScoresCommitMessages(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{{name: "alpha", commits: 3, files: []string{"a.go"}}})
+
+	result, err := newFakeGitHubService(t, server, config.DefaultGitHubConfig()).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	// Fake messages such as "commit 1" are too short and not imperative
+	quality := result.CommitMessages
+	if quality == nil || quality.Messages != 3 || quality.ScoreHistogram[0] != 3 || quality.ImperativeRatio != 0 {
+		t.Fatalf("Unexpected commit message quality: %+v", quality)
+	}
+	if repo := result.RepoStats.OriginalRepos[0]; repo.CommitMessages == nil || repo.CommitMessages.Messages != 3 {
+		t.Errorf("Unexpected commit message quality for %s: %+v", repo.Name, repo.CommitMessages)
+	}
+}
+
+func TestPerformFullAuditSelectsRecentlyPushedRepos(t *testing.T) {
+	repos := []fakeRepo{
+		{name: "alpha", commits: 1, files: []string{"a.go"}},
+		{name: "beta", commits: 1, files: []string{"b.go"}, stalePush: true},
+		{name: "gamma", commits: 1, files: []string{"c.go"}, archived: true},
+	}
+
+	testCases := []struct {
+		name            string
+		includeArchived bool
+		expected        string
+	}{
+		{"archived included", true, "[alpha gamma]"},
+		{"archived skipped", false, "[alpha]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeGitHubServer(t, "octocat", repos)
+			cfg := config.DefaultGitHubConfig()
+			cfg.IncludeArchived = tc.includeArchived
+
+			result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("PerformFullAudit() failed: %v", err)
+			}
+
+			// beta was updated recently but last pushed years ago
+			if analyzed := fmt.Sprint(result.AnalysisSummary.ReposAnalyzedForCode); analyzed != tc.expected {
+				t.Errorf("Analyzed repos = %s, expected %s", analyzed, tc.expected)
+			}
+			if archived := result.RepoStats.Statistics.ArchivedRepos; archived != 1 {
+				t.Errorf("Expected 1 archived repository, got %d", archived)
+			}
+
+			alpha := result.RepoStats.OriginalRepos[0]
+			if alpha.License != "MIT" || fmt.Sprint(alpha.Topics) != "[cli alpha]" || alpha.SizeKB != 120 || alpha.OpenIssues != 4 ||
+				alpha.ForksCount != 3 || alpha.Watchers != 2 || alpha.DefaultBranch != "main" || alpha.Homepage != "https://example.com/alpha" || alpha.PushedAt.IsZero() {
+				t.Errorf("Repository metadata not collected: %+v", alpha)
+			}
+		})
 	}
 }
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/dto"
+)
+
+// Most manifests and CI configs read for test detection. The shallowest are read first, and the
+// root build files and workflows usually name the test framework.
+const (
+	maxTestManifests = 8
+	maxCIConfigs     = 5
+)
+
+// testFramework recognizes a test framework from a file of the repository. A framework is
+// detected when a file matches the path pattern and, if set, its content matches the content pattern.
+type testFramework struct {
+	name    string
+	file    *regexp.Regexp
+	content *regexp.Regexp
+}
+
+// Path patterns of manifests that declare test dependencies
+var (
+	packageJSON   = regexp.MustCompile(`(^|/)package\.json$`)
+	pythonDeps    = regexp.MustCompile(`(^|/)(requirements[^/]*\.txt|pyproject\.toml|setup\.cfg|setup\.py|Pipfile)$`)
+	goMod         = regexp.MustCompile(`(^|/)go\.mod$`)
+	gemfile       = regexp.MustCompile(`(^|/)Gemfile$`)
+	jvmBuild      = regexp.MustCompile(`(^|/)(pom\.xml|build\.gradle(\.kts)?)$`)
+	composerJSON  = regexp.MustCompile(`(^|/)composer\.json$`)
+	dotnetProject = regexp.MustCompile(`\.csproj$`)
+	sbtBuild      = regexp.MustCompile(`(^|/)build\.sbt$`)
+	pubspec       = regexp.MustCompile(`(^|/)pubspec\.yaml$`)
+	cmakeLists    = regexp.MustCompile(`(^|/)CMakeLists\.txt$`)
+)
+
+var testFrameworks = []testFramework{
+	{"Jest", regexp.MustCompile(`(^|/)jest\.config\.[cm]?[jt]s$`), nil},
+	{"Jest", packageJSON, regexp.MustCompile(`"jest"\s*:`)},
+	{"Vitest", regexp.MustCompile(`(^|/)vitest\.config\.[cm]?[jt]s$`), nil},
+	{"Vitest", packageJSON, regexp.MustCompile(`"vitest"\s*:`)},
+	{"Mocha", regexp.MustCompile(`(^|/)\.mocharc\.(js|cjs|json|ya?ml)$`), nil},
+	{"Mocha", packageJSON, regexp.MustCompile(`"mocha"\s*:`)},
+	{"Jasmine", packageJSON, regexp.MustCompile(`"jasmine(-core)?"\s*:`)},
+	{"Karma", regexp.MustCompile(`(^|/)karma\.conf\.[jt]s$`), nil},
+	{"Cypress", regexp.MustCompile(`(^|/)cypress\.(config\.[cm]?[jt]s|json)$`), nil},
+	{"Cypress", packageJSON, regexp.MustCompile(`"cypress"\s*:`)},
+	{"Playwright", regexp.MustCompile(`(^|/)playwright\.config\.[cm]?[jt]s$`), nil},
+	{"Playwright", packageJSON, regexp.MustCompile(`"@playwright/test"\s*:`)},
+	{"pytest", regexp.MustCompile(`(^|/)(pytest\.ini|conftest\.py)$`), nil},
+	{"pytest", pythonDeps, regexp.MustCompile(`\bpytest\b`)},
+	{"tox", regexp.MustCompile(`(^|/)tox\.ini$`), nil},
+	{"nox", regexp.MustCompile(`(^|/)noxfile\.py$`), nil},
+	{"testify", goMod, regexp.MustCompile(`github\.com/stretchr/testify\b`)},
+	{"Ginkgo", goMod, regexp.MustCompile(`github\.com/onsi/ginkgo\b`)},
+	{"RSpec", regexp.MustCompile(`(^|/)\.rspec$`), nil},
+	{"RSpec", gemfile, regexp.MustCompile(`\brspec\b`)},
+	{"Minitest", gemfile, regexp.MustCompile(`\bminitest\b`)},
+	{"JUnit", jvmBuild, regexp.MustCompile(`\bjunit\b`)},
+	{"TestNG", jvmBuild, regexp.MustCompile(`\btestng\b`)},
+	{"Kotest", jvmBuild, regexp.MustCompile(`\bio\.kotest\b`)},
+	{"PHPUnit", regexp.MustCompile(`(^|/)phpunit\.xml(\.dist)?$`), nil},
+	{"PHPUnit", composerJSON, regexp.MustCompile(`"phpunit/phpunit"`)},
+	{"xUnit", dotnetProject, regexp.MustCompile(`"xunit"`)},
+	{"NUnit", dotnetProject, regexp.MustCompile(`"NUnit"`)},
+	{"MSTest", dotnetProject, regexp.MustCompile(`"MSTest\.TestFramework"`)},
+	{"ScalaTest", sbtBuild, regexp.MustCompile(`\bscalatest\b`)},
+	{"Dart test", pubspec, regexp.MustCompile(`(?m)^\s+(test|flutter_test):`)},
+	{"GoogleTest", cmakeLists, regexp.MustCompile(`\b(GTest|gtest_main)\b`)},
+	{"Catch2", cmakeLists, regexp.MustCompile(`\bCatch2\b`)},
+}
+
+// CI systems and the path patterns of their configuration files
+var ciSystems = []struct {
+	name string
+	file *regexp.Regexp
+}{
+	{"GitHub Actions", regexp.MustCompile(`^\.github/workflows/[^/]+\.ya?ml$`)},
+	{"GitLab CI", regexp.MustCompile(`^\.gitlab-ci\.ya?ml$`)},
+	{"Travis CI", regexp.MustCompile(`^\.travis\.ya?ml$`)},
+	{"CircleCI", regexp.MustCompile(`^\.circleci/config\.ya?ml$`)},
+	{"Jenkins", regexp.MustCompile(`(^|/)Jenkinsfile$`)},
+	{"Azure Pipelines", regexp.MustCompile(`^azure-pipelines\.ya?ml$`)},
+	{"Bitbucket Pipelines", regexp.MustCompile(`^bitbucket-pipelines\.ya?ml$`)},
+	{"Drone", regexp.MustCompile(`^\.drone\.ya?ml$`)},
+	{"AppVeyor", regexp.MustCompile(`^\.?appveyor\.ya?ml$`)},
+}
+
+// ciTestCommand matches commands that run tests in a CI configuration
+var ciTestCommand = regexp.MustCompile(`\b(go test|gotestsum|(npm|yarn|pnpm|bun)( run)? test|npx (jest|vitest|mocha|playwright|cypress)|pytest|tox|nox|` +
+	`cargo (test|nextest)|mvnw?\b.*\b(test|verify)|gradlew?\b.*\b(test|check)|rspec|rake (test|spec)|phpunit|dotnet test|` +
+	`mix test|make (test|check)|ctest|(flutter|dart|swift) test|sbt\b.*\btest|xcodebuild\b.*\btest)\b`)
+
+// detectTests detects the tests of a repository from the paths in its tree, its test framework
+// manifests and its CI configuration. Manifests and CI files are read into files, keyed by path;
+// those that can't be read are skipped.
+func (s *GitHubService) detectTests(ctx context.Context, username, repoName string, tree *github.Tree, files map[string]string) *dto.RepoTests {
+	tests := &dto.RepoTests{}
+	testDirs := make(map[string]bool)
+	frameworks := make(map[string]bool)
+	ciNames := make(map[string]bool)
+	var manifests, ciConfigs []string
+	var testSize, codeSize int
+	sized := true
+
+	for _, entry := range tree.Entries {
+		filePath := entry.GetPath()
+		if entry.GetType() != "blob" {
+			continue
+		}
+		for _, ci := range ciSystems {
+			if ci.file.MatchString(filePath) {
+				ciNames[ci.name] = true
+				ciConfigs = append(ciConfigs, filePath)
+			}
+		}
+		// CI configuration lives in .github, which is otherwise skipped with vendored paths
+		if s.languages.IsVendored(filePath) {
+			continue
+		}
+
+		for _, framework := range testFrameworks {
+			if !framework.file.MatchString(filePath) {
+				continue
+			}
+			if framework.content == nil {
+				frameworks[framework.name] = true
+			} else if !slices.Contains(manifests, filePath) {
+				manifests = append(manifests, filePath)
+			}
+		}
+
+		if !s.languages.IsCode(filePath) || s.languages.IsGenerated(filePath, "") {
+			continue
+		}
+		sized = sized && entry.Size != nil
+		if s.languages.IsTest(filePath) {
+			tests.TestFiles++
+			testSize += entry.GetSize()
+			if dir := s.languages.TestDir(filePath); dir != "" {
+				testDirs[dir] = true
+			}
+		} else {
+			tests.CodeFiles++
+			codeSize += entry.GetSize()
+		}
+	}
+
+	switch {
+	case sized && codeSize > 0:
+		tests.TestRatio = math.Round(float64(testSize)/float64(codeSize)*100) / 100
+	case !sized && tests.CodeFiles > 0:
+		tests.TestRatio = math.Round(float64(tests.TestFiles)/float64(tests.CodeFiles)*100) / 100
+	}
+
+	// Read root manifests first; nested ones usually belong to examples or subprojects
+	sortByDepth(manifests)
+	manifests = manifests[:min(len(manifests), maxTestManifests)]
+	ciConfigs = ciConfigs[:min(len(ciConfigs), maxCIConfigs)]
+	s.fetchFiles(ctx, username, repoName, slices.Concat(manifests, ciConfigs), files)
+
+	for _, filePath := range manifests {
+		for _, framework := range testFrameworks {
+			if framework.content != nil && framework.file.MatchString(filePath) && framework.content.MatchString(files[filePath]) {
+				frameworks[framework.name] = true
+			}
+		}
+	}
+	for _, filePath := range ciConfigs {
+		if ciTestCommand.MatchString(files[filePath]) {
+			tests.CIRunsTests = true
+		}
+	}
+
+	tests.TestDirs = sortedKeys(testDirs)
+	tests.Frameworks = sortedKeys(frameworks)
+	tests.CISystems = sortedKeys(ciNames)
+	return tests
+}
+
+// sortByDepth orders paths from the repository root down, keeping the order of paths at the same depth
+func sortByDepth(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], "/") < strings.Count(paths[j], "/")
+	})
+}
+
+// sortedKeys returns the keys of a set in sorted order, or nil for an empty set
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
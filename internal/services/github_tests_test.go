@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"dev_profiler/internal/config"
+)
+
+func TestPerformFullAuditDetectsTests(t *testing.T) {
+	server := newFakeGitHubServer(t, "octocat", []fakeRepo{
+		{
+			name:    "alpha",
+			commits: 1,
+			files: []string{
+				"main.go", "server/handler.go", "server/handler_test.go", "tests/e2e_test.go",
+				"web/package.json", "web/app.js", "go.mod", ".github/workflows/ci.yml", "vendor/pkg/pkg_test.go",
+			},
+			contents: map[string]string{
+				"web/package.json":         `{"devDependencies": {"jest": "^29.0.0"}}`,
+				"go.mod":                   "module example.com/alpha\n\nrequire github.com/stretchr/testify v1.9.0\n",
+				".github/workflows/ci.yml": "jobs:\n  test:\n    steps:\n      - run: go test ./...\n",
+			},
+		},
+		{
+			name:    "beta",
+			commits: 1,
+			files:   []string{"main.py", ".travis.yml"},
+			contents: map[string]string{
+				".travis.yml": "language: python\nscript: python main.py\n",
+			},
+		},
+	})
+	cfg := config.DefaultGitHubConfig()
+	cfg.SampledRepoCount = 2
+
+	result, err := newFakeGitHubService(t, server, cfg).PerformFullAudit(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("PerformFullAudit() failed: %v", err)
+	}
+
+	tests := make(map[string]string)
+	for _, repo := range result.RepoStats.OriginalRepos {
+		if repo.Tests != nil {
+			tests[repo.Name] = fmt.Sprintf("%+v", *repo.Tests)
+		}
+	}
+	expected := map[string]string{
+		"alpha": "{TestFiles:2 CodeFiles:3 TestRatio:0.67 TestDirs:[tests] Frameworks:[Jest testify] CISystems:[GitHub Actions] CIRunsTests:true}",
+		"beta":  "{TestFiles:0 CodeFiles:1 TestRatio:0 TestDirs:[] Frameworks:[] CISystems:[Travis CI] CIRunsTests:false}",
+	}
+	for name, want := range expected {
+		if tests[name] != want {
+			t.Errorf("Tests of %s = %s, expected %s", name, tests[name], want)
+		}
+	}
+
+	stats := result.RepoStats.Statistics
+	if stats.ReposWithTests != 1 || stats.ReposTestedInCI != 1 {
+		t.Errorf("Expected 1 repository with tests and 1 tested in CI, got %d and %d", stats.ReposWithTests, stats.ReposTestedInCI)
+	}
+
+	for _, file := range result.FileAnalysis {
+		expectedTests := file.Path == "server/handler_test.go" || file.Path == "tests/e2e_test.go"
+		if file.HasTests != expectedTests {
+			t.Errorf("HasTests of %s = %v, expected %v", file.Path, file.HasTests, expectedTests)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"text/template"
 	"time"
@@ -36,12 +37,13 @@ func NewOpenAIService(config *config.OpenAIConfig) *OpenAIService {
 }
 
 // AnalyzeGitHubData sends GitHub audit data to OpenAI for analysis
-func (s *OpenAIService) AnalyzeGitHubData(auditResult *dto.AuditResult) (string, error) {
+func (s *OpenAIService) AnalyzeGitHubData(ctx context.Context, auditResult *dto.AuditResult) (string, error) {
 	if s.client == nil {
 		return "", fmt.Errorf("OpenAI client not initialized - API key required")
 	}
 
-	fmt.Printf("[DEBUG] Starting OpenAI analysis...\n")
+	// Debug output goes to stderr so the audit command can print only the output paths to stdout
+	log.Printf("[DEBUG] Starting OpenAI analysis...")
 
 	// Convert audit result to JSON for the prompt
 	auditJSON, err := json.MarshalIndent(auditResult, "", "  ")
@@ -49,22 +51,22 @@ func (s *OpenAIService) AnalyzeGitHubData(auditResult *dto.AuditResult) (string,
 		return "", fmt.Errorf("failed to marshal audit result: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] Audit JSON size: %d bytes\n", len(auditJSON))
+	log.Printf("[DEBUG] Audit JSON size: %d bytes", len(auditJSON))
 
 	// Create the system prompt (from your original Python tool)
 	systemPrompt := s.getSystemPrompt()
-	fmt.Printf("[DEBUG] System prompt size: %d bytes\n", len(systemPrompt))
-	
+	log.Printf("[DEBUG] System prompt size: %d bytes", len(systemPrompt))
+
 	// Create the user prompt with the audit data
 	userPrompt := fmt.Sprintf("Please analyze the following GitHub user data and provide a comprehensive technical assessment:\n\n```json\n%s\n```", string(auditJSON))
-	fmt.Printf("[DEBUG] User prompt size: %d bytes\n", len(userPrompt))
-	fmt.Printf("[DEBUG] Total prompt size: %d bytes\n", len(systemPrompt)+len(userPrompt))
+	log.Printf("[DEBUG] User prompt size: %d bytes", len(userPrompt))
+	log.Printf("[DEBUG] Total prompt size: %d bytes", len(systemPrompt)+len(userPrompt))
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	// Limit the request duration while still honoring cancellation by the caller
+	ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
 	defer cancel()
 
-	fmt.Printf("[DEBUG] Sending request to OpenAI (model: %s)...\n", s.config.Model)
+	log.Printf("[DEBUG] Sending request to OpenAI (model: %s)...", s.config.Model)
 
 	// Create the chat completion request
 	resp, err := s.client.CreateChatCompletion(
@@ -87,20 +89,20 @@ func (s *OpenAIService) AnalyzeGitHubData(auditResult *dto.AuditResult) (string,
 	)
 
 	if err != nil {
-		fmt.Printf("[DEBUG] OpenAI API error: %v\n", err)
+		log.Printf("[DEBUG] OpenAI API error: %v", err)
 		return "", fmt.Errorf("OpenAI API error: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] Received response from OpenAI\n")
+	log.Printf("[DEBUG] Received response from OpenAI")
 
 	if len(resp.Choices) == 0 {
-		fmt.Printf("[DEBUG] No choices in response\n")
+		log.Printf("[DEBUG] No choices in response")
 		return "", fmt.Errorf("no response from OpenAI")
 	}
 
 	responseContent := resp.Choices[0].Message.Content
-	fmt.Printf("[DEBUG] Response content size: %d bytes\n", len(responseContent))
-	fmt.Printf("[DEBUG] OpenAI analysis completed successfully\n")
+	log.Printf("[DEBUG] Response content size: %d bytes", len(responseContent))
+	log.Printf("[DEBUG] OpenAI analysis completed successfully")
 
 	return responseContent, nil
 }
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"dev_profiler/internal/config"
@@ -238,18 +239,18 @@ func TestAnalyzeGitHubDataWithoutClient(t *testing.T) {
 	cfg := &config.OpenAIConfig{
 		APIKey: "", // Empty API key means no client
 	}
-	
+
 	service := NewOpenAIService(cfg)
-	
+
 	auditResult := &dto.AuditResult{
 		UserInfo: dto.UserInfo{Username: "testuser"},
 	}
-	
-	_, err := service.AnalyzeGitHubData(auditResult)
+
+	_, err := service.AnalyzeGitHubData(context.Background(), auditResult)
 	if err == nil {
 		t.Error("AnalyzeGitHubData() should fail when client is not initialized")
 	}
-	
+
 	expectedErrMsg := "OpenAI client not initialized - API key required"
 	if err.Error() != expectedErrMsg {
 		t.Errorf("Expected error message: %s, Got: %s", expectedErrMsg, err.Error())
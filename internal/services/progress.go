@@ -0,0 +1,138 @@
+package services
+
+import (
+	"math"
+	"time"
+)
+
+// ProgressKind identifies the kind of work reported by a progress event
+type ProgressKind string
+
+const (
+	ProgressUser         ProgressKind = "user"
+	ProgressRepositories ProgressKind = "repositories"
+	ProgressRepoStarted  ProgressKind = "repo_started"
+	ProgressCommits      ProgressKind = "commits"
+	ProgressFiles        ProgressKind = "files"
+	ProgressRepoDone     ProgressKind = "repo_done"
+	ProgressRetry        ProgressKind = "retry"
+	ProgressRateLimit    ProgressKind = "rate_limit"
+	ProgressActivity     ProgressKind = "activity" // Pull requests, reviews and other activity outside the analyzed repositories
+)
+
+// ProgressEvent describes a unit of work performed during an audit
+type ProgressEvent struct {
+	Kind      ProgressKind
+	Message   string
+	Repo      string
+	RepoIndex int // 1-based position of Repo among the analyzed repositories
+	RepoTotal int
+	Count     int // Items completed so far (commits, files, repositories)
+	Total     int
+	Attempt   int           // Retry attempt number for ProgressRetry
+	Wait      time.Duration // Delay before the next attempt or rate-limit resume
+	Fraction  float64       // Overall audit completion from 0.0 to 1.0
+}
+
+// ProgressFunc receives audit progress events. It may be called from several goroutines at once.
+type ProgressFunc func(ProgressEvent)
+
+// Share of a repository's work spent on commits, the rest goes to file sampling
+const commitProgressShare = 0.6
+
+// Share of the overall audit spent before the per-repository analysis starts
+const setupProgressShare = 0.1
+
+// auditProgress tracks per-repository completion to derive the overall audit fraction.
+// It is only accessed through reportProgress, which serializes updates.
+type auditProgress struct {
+	repoIndex  map[string]int
+	completion []float64
+	fraction   float64
+}
+
+// newAuditProgress creates a tracker for the repositories selected for analysis
+func newAuditProgress(repoNames []string) *auditProgress {
+	p := &auditProgress{
+		repoIndex:  make(map[string]int, len(repoNames)),
+		completion: make([]float64, len(repoNames)),
+		fraction:   setupProgressShare,
+	}
+	for i, name := range repoNames {
+		p.repoIndex[name] = i
+	}
+	return p
+}
+
+// update records the event and fills in its repository position and overall fraction
+func (p *auditProgress) update(event *ProgressEvent) {
+	event.RepoTotal = len(p.completion)
+	index, ok := p.repoIndex[event.Repo]
+	if ok {
+		event.RepoIndex = index + 1
+
+		completion := p.completion[index]
+		switch event.Kind {
+		case ProgressRepoStarted:
+			completion = 0
+		case ProgressCommits:
+			// Concurrent workers may deliver counts out of order, so never move backwards
+			completion = math.Max(completion, commitProgressShare*ratio(event.Count, event.Total))
+		case ProgressFiles:
+			completion = math.Max(completion, commitProgressShare+(1-commitProgressShare)*ratio(event.Count, event.Total))
+		case ProgressRepoDone:
+			completion = 1
+		}
+		p.completion[index] = completion
+
+		sum := 0.0
+		for _, c := range p.completion {
+			sum += c
+		}
+		p.fraction = setupProgressShare + (1-setupProgressShare)*sum/float64(len(p.completion))
+	}
+
+	event.Fraction = p.fraction
+}
+
+// ratio returns count/total, treating an empty total as complete
+func ratio(count, total int) float64 {
+	if total <= 0 {
+		return 1
+	}
+	return float64(count) / float64(total)
+}
+
+// SetProgressCallback sets the callback that receives audit progress events
+func (s *GitHubService) SetProgressCallback(callback ProgressFunc) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.progress = callback
+}
+
+// setProgressTracker installs the tracker used to compute overall audit completion
+func (s *GitHubService) setProgressTracker(tracker *auditProgress) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.tracker = tracker
+}
+
+// reportProgress delivers a progress event to the registered callback, if any. Events without a
+// fraction, such as retries outside the repository analysis, carry the last reported one forward.
+// The callback is called without holding the lock so it can't block other workers.
+func (s *GitHubService) reportProgress(event ProgressEvent) {
+	s.progressMu.Lock()
+	switch {
+	case s.tracker != nil:
+		s.tracker.update(&event)
+	case event.Fraction == 0:
+		event.Fraction = s.lastFraction
+	}
+	s.lastFraction = event.Fraction
+	callback := s.progress
+	s.progressMu.Unlock()
+
+	if callback != nil {
+		callback(event)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"dev_profiler/internal/config"
+)
+
+func TestAuditProgressFraction(t *testing.T) {
+	tracker := newAuditProgress([]string{"repo-a", "repo-b"})
+
+	testCases := []struct {
+		name     string
+		event    ProgressEvent
+		index    int
+		fraction float64
+	}{
+		{"repo started", ProgressEvent{Kind: ProgressRepoStarted, Repo: "repo-a"}, 1, 0.1},
+		{"half of commits", ProgressEvent{Kind: ProgressCommits, Repo: "repo-a", Count: 5, Total: 10}, 1, 0.1 + 0.9*0.3/2},
+		{"all files", ProgressEvent{Kind: ProgressFiles, Repo: "repo-a", Count: 2, Total: 2}, 1, 0.1 + 0.9*1/2},
+		{"unknown repo keeps fraction", ProgressEvent{Kind: ProgressRetry, Repo: "other"}, 0, 0.1 + 0.9*1/2},
+		{"second repo done", ProgressEvent{Kind: ProgressRepoDone, Repo: "repo-b"}, 2, 1.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := tc.event
+			tracker.update(&event)
+
+			if event.RepoIndex != tc.index {
+				t.Errorf("Expected repo index %d, got %d", tc.index, event.RepoIndex)
+			}
+			if event.RepoTotal != 2 {
+				t.Errorf("Expected repo total 2, got %d", event.RepoTotal)
+			}
+			if diff := event.Fraction - tc.fraction; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected fraction %.4f, got %.4f", tc.fraction, event.Fraction)
+			}
+		})
+	}
+}
+
+func TestReportProgressWithoutTracker(t *testing.T) {
+	service := NewGitHubService(&config.GitHubConfig{})
+
+	var received []ProgressEvent
+	service.SetProgressCallback(func(event ProgressEvent) {
+		received = append(received, event)
+	})
+
+	service.reportProgress(ProgressEvent{Kind: ProgressUser, Fraction: 0.02})
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(received))
+	}
+	if received[0].Fraction != 0.02 {
+		t.Errorf("Fraction should be kept without a tracker, got %f", received[0].Fraction)
+	}
+
+	// A retry reported before the tracker is installed doesn't move the progress backwards
+	service.reportProgress(ProgressEvent{Kind: ProgressRetry, Attempt: 1})
+	if received[1].Fraction != 0.02 {
+		t.Errorf("Fraction of an event without one should carry the last fraction, got %f", received[1].Fraction)
+	}
+}
+
+func TestReportProgressCallsBackWithoutLock(t *testing.T) {
+	service := NewGitHubService(&config.GitHubConfig{})
+
+	var nested int
+	service.SetProgressCallback(func(event ProgressEvent) {
+		// Reporting from the callback would deadlock if the lock were held
+		if event.Kind == ProgressUser {
+			service.reportProgress(ProgressEvent{Kind: ProgressActivity})
+		}
+		nested++
+	})
+	service.reportProgress(ProgressEvent{Kind: ProgressUser, Fraction: 0.02})
+
+	if nested != 2 {
+		t.Errorf("Expected 2 events, got %d", nested)
+	}
+}
+
+func TestCallWithRetry(t *testing.T) {
+	originalDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = originalDelay }()
+
+	serverError := &github.Response{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	notFound := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+
+	testCases := []struct {
+		name      string
+		responses []*github.Response
+		errs      []error
+		calls     int
+		retries   int
+		wantErr   bool
+	}{
+		{"success", []*github.Response{nil}, []error{nil}, 1, 0, false},
+		{"recovers from server error", []*github.Response{serverError, nil}, []error{errors.New("bad gateway"), nil}, 2, 1, false},
+		{"recovers from network error", []*github.Response{nil, nil}, []error{&url.Error{Op: "Get", Err: errors.New("reset")}, nil}, 2, 1, false},
+		{"gives up after max attempts", []*github.Response{serverError, serverError, serverError}, []error{errors.New("a"), errors.New("b"), errors.New("c")}, 3, 2, true},
+		{"does not retry client errors", []*github.Response{notFound}, []error{errors.New("not found")}, 1, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewGitHubService(&config.GitHubConfig{})
+			retries := 0
+			service.SetProgressCallback(func(event ProgressEvent) {
+				if event.Kind == ProgressRetry {
+					retries++
+				}
+			})
+
+			calls := 0
+			err := service.callWithRetry(context.Background(), "repo", "test call", func() (*github.Response, error) {
+				resp, err := tc.responses[calls], tc.errs[calls]
+				calls++
+				return resp, err
+			})
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+			if calls != tc.calls {
+				t.Errorf("Expected %d calls, got %d", tc.calls, calls)
+			}
+			if retries != tc.retries {
+				t.Errorf("Expected %d retry events, got %d", tc.retries, retries)
+			}
+		})
+	}
+}
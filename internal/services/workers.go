@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// runBounded calls fn for every index in [0, n) using at most limit concurrent workers.
+// Callers store results by index so the output order does not depend on scheduling.
+// No new work is started once ctx is cancelled, in which case ctx.Err() is returned.
+func runBounded(ctx context.Context, n, limit int, fn func(i int)) error {
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > n {
+		limit = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return ctx.Err()
+}
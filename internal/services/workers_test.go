@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBounded(t *testing.T) {
+	testCases := []struct {
+		name  string
+		n     int
+		limit int
+	}{
+		{"more items than workers", 20, 3},
+		{"fewer items than workers", 2, 5},
+		{"zero limit runs serially", 5, 0},
+		{"no items", 0, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var running, peak int32
+			results := make([]int, tc.n)
+
+			err := runBounded(context.Background(), tc.n, tc.limit, func(i int) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				results[i] = i * i
+				atomic.AddInt32(&running, -1)
+			})
+			if err != nil {
+				t.Fatalf("runBounded() failed: %v", err)
+			}
+
+			limit := tc.limit
+			if limit < 1 {
+				limit = 1
+			}
+			if int(peak) > limit {
+				t.Errorf("Expected at most %d concurrent workers, got %d", limit, peak)
+			}
+			for i, result := range results {
+				if result != i*i {
+					t.Errorf("Result %d was not computed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBoundedCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	processed := 0
+	err := runBounded(ctx, 100, 2, func(i int) {
+		mu.Lock()
+		processed++
+		if processed == 5 {
+			cancel()
+		}
+		mu.Unlock()
+	})
+
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if processed >= 100 {
+		t.Error("No new work should be started after cancellation")
+	}
+}
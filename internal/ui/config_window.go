@@ -1,35 +1,61 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"dev_profiler/internal/config"
+	"dev_profiler/internal/languages"
 )
 
 // ConfigWindowUI represents the UI components for the configuration window
 type ConfigWindowUI struct {
 	// GitHub configuration
-	TokenEntry            *widget.Entry
-	SampledRepoCountEntry *widget.Entry
-	CommitsPerRepoEntry   *widget.Entry
-	SampleFileCountEntry  *widget.Entry
-	AnalysisYearsEntry    *widget.Entry
-	IncludePrivateCheck   *widget.Check
-	RandomSeedEntry       *widget.Entry
-	SaveDebugJSONCheck    *widget.Check
+	TokenEntry               *widget.Entry
+	SampledRepoCountEntry    *widget.Entry
+	CommitsPerRepoEntry      *widget.Entry
+	SampleFileCountEntry     *widget.Entry
+	PullRequestCountEntry    *widget.Entry
+	IssueCountEntry          *widget.Entry
+	ExternalRepoCountEntry   *widget.Entry
+	SampleExternalDiffsCheck *widget.Check
+	AnalysisYearsEntry       *widget.Entry
+	IncludePrivateCheck      *widget.Check
+	IncludeArchivedCheck     *widget.Check
+	OwnedReposOnlyCheck      *widget.Check
+	RandomSeedEntry          *widget.Entry
+	SaveDebugJSONCheck       *widget.Check
+	RepoConcurrencyEntry     *widget.Entry
+	CommitConcurrencyEntry   *widget.Entry
+	FileConcurrencyEntry     *widget.Entry
+	CacheEnabledCheck        *widget.Check
+	CacheTTLEntry            *widget.Entry
+	ClearCacheButton         *widget.Button
+	CollectorBackendSelect   *widget.Select
+	SampleSourceSelect       *widget.Select
+	SamplingStrategySelect   *widget.Select
+	DiffBudgetEntry          *widget.Entry
+	EstimateOwnershipCheck   *widget.Check
+	LanguageOverridesEntry   *widget.Entry
+	ExcludedPathsEntry       *widget.Entry
+	AuthorAliasesEntry       *widget.Entry
 	// OpenAI configuration
-	OpenAIKeyEntry        *widget.Entry
-	OpenAIModelEntry      *widget.Entry
-	SystemPromptEntry     *widget.Entry
-	HTMLTemplateEntry     *widget.Entry
-	CSSStylesEntry        *widget.Entry
+	OpenAIKeyEntry    *widget.Entry
+	OpenAIModelEntry  *widget.Entry
+	SystemPromptEntry *widget.Entry
+	HTMLTemplateEntry *widget.Entry
+	CSSStylesEntry    *widget.Entry
 	// Buttons
-	SaveButton            *widget.Button
-	CancelButton          *widget.Button
+	SaveButton   *widget.Button
+	CancelButton *widget.Button
 }
 
 // NewConfigWindowUI creates a new ConfigWindowUI instance
@@ -75,54 +101,112 @@ func (ui *ConfigWindowUI) initializeComponents() {
 	// GitHub token
 	ui.TokenEntry = widget.NewPasswordEntry()
 	ui.TokenEntry.SetPlaceHolder("Enter GitHub API token (required)")
-	
+
 	// Analysis parameters
 	ui.SampledRepoCountEntry = widget.NewEntry()
 	ui.SampledRepoCountEntry.SetPlaceHolder("10")
-	
+
 	ui.CommitsPerRepoEntry = widget.NewEntry()
 	ui.CommitsPerRepoEntry.SetPlaceHolder("50")
-	
+
 	ui.SampleFileCountEntry = widget.NewEntry()
 	ui.SampleFileCountEntry.SetPlaceHolder("5")
-	
+
+	ui.PullRequestCountEntry = widget.NewEntry()
+	ui.PullRequestCountEntry.SetPlaceHolder("30")
+
+	ui.IssueCountEntry = widget.NewEntry()
+	ui.IssueCountEntry.SetPlaceHolder("30")
+
+	ui.ExternalRepoCountEntry = widget.NewEntry()
+	ui.ExternalRepoCountEntry.SetPlaceHolder("10")
+
+	ui.SampleExternalDiffsCheck = widget.NewCheck("Sample the user's patches from external repositories", nil)
+
 	ui.AnalysisYearsEntry = widget.NewEntry()
 	ui.AnalysisYearsEntry.SetPlaceHolder("2")
-	
-	ui.IncludePrivateCheck = widget.NewCheck("Include private repositories", nil)
-	
+
+	ui.IncludePrivateCheck = widget.NewCheck("Include private repositories (when auditing the token owner)", nil)
+
+	ui.IncludeArchivedCheck = widget.NewCheck("Analyze archived repositories", nil)
+
+	ui.OwnedReposOnlyCheck = widget.NewCheck("Only repositories the user owns (skip collaborations)", nil)
+
 	ui.RandomSeedEntry = widget.NewEntry()
 	ui.RandomSeedEntry.SetPlaceHolder("42")
-	
+
 	ui.SaveDebugJSONCheck = widget.NewCheck("Save debug JSON file (raw GitHub data)", nil)
-	
+
+	// Concurrency limits
+	ui.RepoConcurrencyEntry = widget.NewEntry()
+	ui.RepoConcurrencyEntry.SetPlaceHolder("3")
+
+	ui.CommitConcurrencyEntry = widget.NewEntry()
+	ui.CommitConcurrencyEntry.SetPlaceHolder("5")
+
+	ui.FileConcurrencyEntry = widget.NewEntry()
+	ui.FileConcurrencyEntry.SetPlaceHolder("5")
+
+	// Response cache
+	ui.CacheEnabledCheck = widget.NewCheck("Cache GitHub API responses on disk", nil)
+
+	ui.CacheTTLEntry = widget.NewEntry()
+	ui.CacheTTLEntry.SetPlaceHolder("24")
+
+	ui.ClearCacheButton = widget.NewButtonWithIcon("Clear Cache", theme.DeleteIcon(), nil)
+
+	// Data collection backend
+	ui.CollectorBackendSelect = widget.NewSelect([]string{config.CollectorREST, config.CollectorGraphQL}, nil)
+
+	// Code sample source
+	ui.SampleSourceSelect = widget.NewSelect([]string{config.SampleSourceFiles, config.SampleSourceDiffs}, nil)
+
+	// File sampling strategy
+	ui.SamplingStrategySelect = widget.NewSelect(config.SamplingStrategies, nil)
+
+	ui.DiffBudgetEntry = widget.NewEntry()
+	ui.DiffBudgetEntry.SetPlaceHolder("6000")
+
+	ui.EstimateOwnershipCheck = widget.NewCheck("Prefer files written by the user (estimate ownership with blame)", nil)
+
+	// Code file filters
+	ui.LanguageOverridesEntry = widget.NewMultiLineEntry()
+	ui.LanguageOverridesEntry.SetPlaceHolder("One .ext=Language or filename=Language per line, e.g. .tpl=Go")
+
+	ui.ExcludedPathsEntry = widget.NewMultiLineEntry()
+	ui.ExcludedPathsEntry.SetPlaceHolder("One glob pattern of paths never sampled per line, e.g. legacy/*")
+
+	// Commit attribution
+	ui.AuthorAliasesEntry = widget.NewMultiLineEntry()
+	ui.AuthorAliasesEntry.SetPlaceHolder("One git author email or name of the user per line, e.g. octo@personal.example")
+
 	// OpenAI configuration
 	ui.OpenAIKeyEntry = widget.NewPasswordEntry()
 	ui.OpenAIKeyEntry.SetPlaceHolder("Enter OpenAI API key (required for LLM analysis)")
-	
+
 	ui.OpenAIModelEntry = widget.NewEntry()
 	ui.OpenAIModelEntry.SetPlaceHolder("gpt-4o")
-	
+
 	ui.SystemPromptEntry = widget.NewMultiLineEntry()
 	ui.SystemPromptEntry.SetPlaceHolder("Enter custom system prompt for GitHub user evaluation...")
-	ui.SystemPromptEntry.Wrapping = fyne.TextWrapOff // Disable line wrapping
+	ui.SystemPromptEntry.Wrapping = fyne.TextWrapOff                 // Disable line wrapping
 	ui.SystemPromptEntry.TextStyle = fyne.TextStyle{Monospace: true} // Use monospace font
 	// Remove fixed resize to allow proper expansion in tab layout
-	
+
 	ui.HTMLTemplateEntry = widget.NewMultiLineEntry()
 	ui.HTMLTemplateEntry.SetPlaceHolder("Enter custom HTML template for reports...")
-	ui.HTMLTemplateEntry.Wrapping = fyne.TextWrapOff // Disable line wrapping
+	ui.HTMLTemplateEntry.Wrapping = fyne.TextWrapOff                 // Disable line wrapping
 	ui.HTMLTemplateEntry.TextStyle = fyne.TextStyle{Monospace: true} // Use monospace font
-	
+
 	ui.CSSStylesEntry = widget.NewMultiLineEntry()
 	ui.CSSStylesEntry.SetPlaceHolder("Enter custom CSS styles for reports...")
-	ui.CSSStylesEntry.Wrapping = fyne.TextWrapOff // Disable line wrapping
+	ui.CSSStylesEntry.Wrapping = fyne.TextWrapOff                 // Disable line wrapping
 	ui.CSSStylesEntry.TextStyle = fyne.TextStyle{Monospace: true} // Use monospace font
-	
+
 	// Buttons
 	ui.SaveButton = widget.NewButton("Save", nil)
 	ui.SaveButton.Importance = widget.HighImportance
-	
+
 	ui.CancelButton = widget.NewButton("Cancel", nil)
 }
 
@@ -130,37 +214,111 @@ func (ui *ConfigWindowUI) initializeComponents() {
 func (ui *ConfigWindowUI) createParametersSection() *fyne.Container {
 	title := widget.NewLabel("Analysis Parameters")
 	title.TextStyle = fyne.TextStyle{Bold: true}
-	
+
 	// Create form-like layout for parameters
 	sampledRepoLabel := widget.NewLabel("Repositories to analyze:")
 	sampledRepoContainer := container.NewBorder(nil, nil, sampledRepoLabel, nil, ui.SampledRepoCountEntry)
-	
+
 	commitsLabel := widget.NewLabel("Commits per repository:")
 	commitsContainer := container.NewBorder(nil, nil, commitsLabel, nil, ui.CommitsPerRepoEntry)
-	
+
 	filesLabel := widget.NewLabel("Files to sample per repo:")
 	filesContainer := container.NewBorder(nil, nil, filesLabel, nil, ui.SampleFileCountEntry)
-	
+
+	pullRequestsLabel := widget.NewLabel("Pull requests and reviews to collect:")
+	pullRequestsContainer := container.NewBorder(nil, nil, pullRequestsLabel, nil, ui.PullRequestCountEntry)
+
+	issuesLabel := widget.NewLabel("Issues opened or commented on to collect:")
+	issuesContainer := container.NewBorder(nil, nil, issuesLabel, nil, ui.IssueCountEntry)
+
+	externalReposLabel := widget.NewLabel("External repositories to report:")
+	externalReposContainer := container.NewBorder(nil, nil, externalReposLabel, nil, ui.ExternalRepoCountEntry)
+
 	yearsLabel := widget.NewLabel("Years of activity to consider:")
 	yearsContainer := container.NewBorder(nil, nil, yearsLabel, nil, ui.AnalysisYearsEntry)
-	
+
 	seedLabel := widget.NewLabel("Random seed:")
 	seedContainer := container.NewBorder(nil, nil, seedLabel, nil, ui.RandomSeedEntry)
-	
+
+	repoConcurrencyLabel := widget.NewLabel("Repositories analyzed in parallel:")
+	repoConcurrencyContainer := container.NewBorder(nil, nil, repoConcurrencyLabel, nil, ui.RepoConcurrencyEntry)
+
+	commitConcurrencyLabel := widget.NewLabel("Parallel commit requests per repo:")
+	commitConcurrencyContainer := container.NewBorder(nil, nil, commitConcurrencyLabel, nil, ui.CommitConcurrencyEntry)
+
+	fileConcurrencyLabel := widget.NewLabel("Parallel file requests per repo:")
+	fileConcurrencyContainer := container.NewBorder(nil, nil, fileConcurrencyLabel, nil, ui.FileConcurrencyEntry)
+
+	backendLabel := widget.NewLabel("Data collection API:")
+	backendContainer := container.NewBorder(nil, nil, backendLabel, nil, ui.CollectorBackendSelect)
+
+	sampleSourceLabel := widget.NewLabel("Code samples (whole files or the user's diffs):")
+	sampleSourceContainer := container.NewBorder(nil, nil, sampleSourceLabel, nil, ui.SampleSourceSelect)
+
+	samplingStrategyLabel := widget.NewLabel("File sampling strategy:")
+	samplingStrategyContainer := container.NewBorder(nil, nil, samplingStrategyLabel, nil, ui.SamplingStrategySelect)
+
+	diffBudgetLabel := widget.NewLabel("Diff characters per repository:")
+	diffBudgetContainer := container.NewBorder(nil, nil, diffBudgetLabel, nil, ui.DiffBudgetEntry)
+
+	languageOverridesLabel := widget.NewLabel("Language overrides:")
+	excludedPathsLabel := widget.NewLabel("Excluded paths:")
+	authorAliasesLabel := widget.NewLabel("Author aliases:")
+
 	parametersSection := container.NewVBox(
 		title,
 		sampledRepoContainer,
 		commitsContainer,
 		filesContainer,
+		pullRequestsContainer,
+		issuesContainer,
+		externalReposContainer,
+		ui.SampleExternalDiffsCheck,
 		yearsContainer,
 		ui.IncludePrivateCheck,
+		ui.IncludeArchivedCheck,
+		ui.OwnedReposOnlyCheck,
 		seedContainer,
 		ui.SaveDebugJSONCheck,
+		repoConcurrencyContainer,
+		commitConcurrencyContainer,
+		fileConcurrencyContainer,
+		backendContainer,
+		sampleSourceContainer,
+		samplingStrategyContainer,
+		diffBudgetContainer,
+		ui.EstimateOwnershipCheck,
+		languageOverridesLabel,
+		ui.LanguageOverridesEntry,
+		excludedPathsLabel,
+		ui.ExcludedPathsEntry,
+		authorAliasesLabel,
+		ui.AuthorAliasesEntry,
 	)
-	
+
 	return parametersSection
 }
 
+// createCacheSection creates the GitHub API response cache section
+func (ui *ConfigWindowUI) createCacheSection() *fyne.Container {
+	title := widget.NewLabel("Response Cache")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	ttlLabel := widget.NewLabel("Revalidate cached responses after (hours):")
+	ttlContainer := container.NewBorder(nil, nil, ttlLabel, nil, ui.CacheTTLEntry)
+
+	help := widget.NewLabel("Unchanged data is confirmed with conditional requests, which don't count against the API quota")
+	help.TextStyle = fyne.TextStyle{Italic: true}
+
+	return container.NewVBox(
+		title,
+		ui.CacheEnabledCheck,
+		ttlContainer,
+		help,
+		container.NewHBox(ui.ClearCacheButton),
+	)
+}
+
 // createButtonsSection creates the buttons section
 func (ui *ConfigWindowUI) createButtonsSection() *fyne.Container {
 	buttonsContainer := container.NewHBox(
@@ -210,7 +368,8 @@ func (ui *ConfigWindowUI) createCredentialsTab() *fyne.Container {
 // createGitHubTab creates the GitHub configuration tab with analysis parameters
 func (ui *ConfigWindowUI) createGitHubTab() *fyne.Container {
 	parametersSection := ui.createParametersSection()
-	return container.NewVBox(parametersSection)
+	cacheSection := ui.createCacheSection()
+	return container.NewVBox(parametersSection, widget.NewSeparator(), cacheSection)
 }
 
 // createOpenAITab creates the OpenAI configuration tab with model selector only
@@ -314,15 +473,46 @@ func (ui *ConfigWindowUI) LoadConfig(githubConfig *config.GitHubConfig, openaiCo
 	ui.SampledRepoCountEntry.SetText(strconv.Itoa(githubConfig.SampledRepoCount))
 	ui.CommitsPerRepoEntry.SetText(strconv.Itoa(githubConfig.CommitsPerRepo))
 	ui.SampleFileCountEntry.SetText(strconv.Itoa(githubConfig.SampleFileCount))
+	ui.PullRequestCountEntry.SetText(strconv.Itoa(githubConfig.PullRequestCount))
+	ui.IssueCountEntry.SetText(strconv.Itoa(githubConfig.IssueCount))
+	ui.ExternalRepoCountEntry.SetText(strconv.Itoa(githubConfig.ExternalRepoCount))
+	ui.SampleExternalDiffsCheck.SetChecked(githubConfig.SampleExternalDiffs)
 	ui.AnalysisYearsEntry.SetText(strconv.Itoa(githubConfig.AnalysisYears))
 	ui.IncludePrivateCheck.SetChecked(githubConfig.IncludePrivateRepo)
+	ui.IncludeArchivedCheck.SetChecked(githubConfig.IncludeArchived)
+	ui.OwnedReposOnlyCheck.SetChecked(githubConfig.OwnedReposOnly)
 	ui.RandomSeedEntry.SetText(strconv.Itoa(githubConfig.RandomSeed))
 	ui.SaveDebugJSONCheck.SetChecked(githubConfig.SaveDebugJSON)
-	
+	ui.RepoConcurrencyEntry.SetText(strconv.Itoa(githubConfig.RepoConcurrency))
+	ui.CommitConcurrencyEntry.SetText(strconv.Itoa(githubConfig.CommitConcurrency))
+	ui.FileConcurrencyEntry.SetText(strconv.Itoa(githubConfig.FileConcurrency))
+	ui.CacheEnabledCheck.SetChecked(githubConfig.CacheEnabled)
+	ui.CacheTTLEntry.SetText(strconv.Itoa(githubConfig.CacheTTLHours))
+	if githubConfig.CollectorBackend == config.CollectorGraphQL {
+		ui.CollectorBackendSelect.SetSelected(config.CollectorGraphQL)
+	} else {
+		ui.CollectorBackendSelect.SetSelected(config.CollectorREST)
+	}
+	if githubConfig.SampleSource == config.SampleSourceDiffs {
+		ui.SampleSourceSelect.SetSelected(config.SampleSourceDiffs)
+	} else {
+		ui.SampleSourceSelect.SetSelected(config.SampleSourceFiles)
+	}
+	if slices.Contains(config.SamplingStrategies, githubConfig.SamplingStrategy) {
+		ui.SamplingStrategySelect.SetSelected(githubConfig.SamplingStrategy)
+	} else {
+		ui.SamplingStrategySelect.SetSelected(config.SamplingRandom)
+	}
+	ui.DiffBudgetEntry.SetText(strconv.Itoa(githubConfig.DiffBudgetChars))
+	ui.EstimateOwnershipCheck.SetChecked(githubConfig.EstimateOwnership)
+	ui.LanguageOverridesEntry.SetText(formatLanguageOverrides(githubConfig.LanguageOverrides))
+	ui.ExcludedPathsEntry.SetText(strings.Join(githubConfig.ExcludedPaths, "\n"))
+	ui.AuthorAliasesEntry.SetText(strings.Join(githubConfig.AuthorAliases, "\n"))
+
 	// Load OpenAI configuration
 	ui.OpenAIKeyEntry.SetText(openaiConfig.APIKey)
 	ui.OpenAIModelEntry.SetText(openaiConfig.Model)
-	
+
 	// Load system prompt - show default if no custom prompt is configured
 	if openaiConfig.SystemPrompt != "" {
 		ui.SystemPromptEntry.SetText(openaiConfig.SystemPrompt)
@@ -330,7 +520,7 @@ func (ui *ConfigWindowUI) LoadConfig(githubConfig *config.GitHubConfig, openaiCo
 		// Display default system prompt for discoverability and easier editing
 		ui.SystemPromptEntry.SetText(config.DefaultSystemPrompt())
 	}
-	
+
 	// Load HTML template - show default if no custom template is configured
 	if openaiConfig.HTMLTemplate != "" {
 		ui.HTMLTemplateEntry.SetText(openaiConfig.HTMLTemplate)
@@ -338,7 +528,7 @@ func (ui *ConfigWindowUI) LoadConfig(githubConfig *config.GitHubConfig, openaiCo
 		// Display default HTML template for discoverability and easier editing
 		ui.HTMLTemplateEntry.SetText(config.DefaultHTMLTemplate())
 	}
-	
+
 	// Load CSS styles - show default if no custom styles are configured
 	if openaiConfig.CSSStyles != "" {
 		ui.CSSStylesEntry.SetText(openaiConfig.CSSStyles)
@@ -352,55 +542,154 @@ func (ui *ConfigWindowUI) LoadConfig(githubConfig *config.GitHubConfig, openaiCo
 func (ui *ConfigWindowUI) GetConfig() (*config.GitHubConfig, *config.OpenAIConfig, error) {
 	githubConfig := &config.GitHubConfig{}
 	openaiConfig := &config.OpenAIConfig{}
-	
+
 	// Get GitHub configuration
 	githubConfig.Token = ui.TokenEntry.Text
-	
+
 	var err error
 	githubConfig.SampledRepoCount, err = strconv.Atoi(ui.SampledRepoCountEntry.Text)
 	if err != nil {
 		return nil, nil, err
 	}
-	
+
 	githubConfig.CommitsPerRepo, err = strconv.Atoi(ui.CommitsPerRepoEntry.Text)
 	if err != nil {
 		return nil, nil, err
 	}
-	
+
 	githubConfig.SampleFileCount, err = strconv.Atoi(ui.SampleFileCountEntry.Text)
 	if err != nil {
 		return nil, nil, err
 	}
-	
+
+	githubConfig.PullRequestCount, err = strconv.Atoi(ui.PullRequestCountEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.IssueCount, err = strconv.Atoi(ui.IssueCountEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.ExternalRepoCount, err = strconv.Atoi(ui.ExternalRepoCountEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	githubConfig.AnalysisYears, err = strconv.Atoi(ui.AnalysisYearsEntry.Text)
 	if err != nil {
 		return nil, nil, err
 	}
-	
+
 	githubConfig.RandomSeed, err = strconv.Atoi(ui.RandomSeedEntry.Text)
 	if err != nil {
 		return nil, nil, err
 	}
-	
+
+	githubConfig.RepoConcurrency, err = strconv.Atoi(ui.RepoConcurrencyEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.CommitConcurrency, err = strconv.Atoi(ui.CommitConcurrencyEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.FileConcurrency, err = strconv.Atoi(ui.FileConcurrencyEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.CacheTTLHours, err = strconv.Atoi(ui.CacheTTLEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.DiffBudgetChars, err = strconv.Atoi(ui.DiffBudgetEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.LanguageOverrides, err = parseLanguageOverrides(ui.LanguageOverridesEntry.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.ExcludedPaths = nonEmptyLines(ui.ExcludedPathsEntry.Text)
+	if err := languages.ValidatePatterns(githubConfig.ExcludedPaths); err != nil {
+		return nil, nil, err
+	}
+
+	githubConfig.AuthorAliases = nonEmptyLines(ui.AuthorAliasesEntry.Text)
+
 	githubConfig.IncludePrivateRepo = ui.IncludePrivateCheck.Checked
+	githubConfig.IncludeArchived = ui.IncludeArchivedCheck.Checked
+	githubConfig.OwnedReposOnly = ui.OwnedReposOnlyCheck.Checked
+	githubConfig.CacheEnabled = ui.CacheEnabledCheck.Checked
+	githubConfig.CollectorBackend = ui.CollectorBackendSelect.Selected
+	githubConfig.SampleSource = ui.SampleSourceSelect.Selected
+	githubConfig.SamplingStrategy = ui.SamplingStrategySelect.Selected
+	githubConfig.EstimateOwnership = ui.EstimateOwnershipCheck.Checked
 	githubConfig.SaveDebugJSON = ui.SaveDebugJSONCheck.Checked
-	
+	githubConfig.SampleExternalDiffs = ui.SampleExternalDiffsCheck.Checked
+
 	// Get OpenAI configuration
 	openaiConfig.APIKey = ui.OpenAIKeyEntry.Text
 	openaiConfig.Model = ui.OpenAIModelEntry.Text
-	
+
 	// Always save the current system prompt text
 	// The LoadConfig logic will handle showing default when appropriate
 	openaiConfig.SystemPrompt = ui.SystemPromptEntry.Text
-	
+
 	// Always save the current HTML template and CSS styles text
 	// The LoadConfig logic will handle showing default when appropriate
 	openaiConfig.HTMLTemplate = ui.HTMLTemplateEntry.Text
 	openaiConfig.CSSStyles = ui.CSSStylesEntry.Text
-	
+
 	return githubConfig, openaiConfig, nil
 }
 
+// formatLanguageOverrides formats language overrides as sorted key=Language lines
+func formatLanguageOverrides(overrides map[string]string) string {
+	lines := make([]string, 0, len(overrides))
+	for key, name := range overrides {
+		lines = append(lines, key+"="+name)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// parseLanguageOverrides parses key=Language lines, ignoring blank lines
+func parseLanguageOverrides(text string) (map[string]string, error) {
+	lines := nonEmptyLines(text)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	overrides := make(map[string]string, len(lines))
+	for _, line := range lines {
+		key, name, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid language override %q, expected .ext=Language or filename=Language", line)
+		}
+		overrides[key] = strings.TrimSpace(name)
+	}
+	return overrides, nil
+}
+
+// nonEmptyLines splits text into trimmed lines, dropping blank ones
+func nonEmptyLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 // ResetToDefaults resets all fields to default values
 func (ui *ConfigWindowUI) ResetToDefaults() {
 	githubDefaults := config.DefaultGitHubConfig()
@@ -418,4 +707,7 @@ func (ui *ConfigWindowUI) SetCancelButtonCallback(callback func()) {
 	ui.CancelButton.OnTapped = callback
 }
 
-
+// SetClearCacheButtonCallback sets the callback for the clear cache button
+func (ui *ConfigWindowUI) SetClearCacheButtonCallback(callback func()) {
+	ui.ClearCacheButton.OnTapped = callback
+}
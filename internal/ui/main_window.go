@@ -12,16 +12,17 @@ import (
 // MainWindowUI represents the UI components for the main window
 type MainWindowUI struct {
 	// GitHub user input
-	UsernameEntry  *widget.Entry
-	AnalyzeButton  *widget.Button
-	
+	UsernameEntry *widget.Entry
+	AnalyzeButton *widget.Button
+	CancelButton  *widget.Button
+
 	// Configuration
 	ConfigButton *widget.Button
-	
+
 	// Progress and status
-	ProgressBar    *widget.ProgressBar
-	StatusLabel    *widget.Label
-	
+	ProgressBar *widget.ProgressBar
+	StatusLabel *widget.Label
+
 	// Results display
 	ResultsRichText *widget.RichText
 	ResultsScroll   *container.Scroll
@@ -64,27 +65,31 @@ func (ui *MainWindowUI) initializeComponents() {
 	// Username input
 	ui.UsernameEntry = widget.NewEntry()
 	ui.UsernameEntry.SetPlaceHolder("Enter GitHub username...")
-	
+
 	// Buttons
 	ui.AnalyzeButton = widget.NewButton("Analyze GitHub Profile", nil)
 	ui.AnalyzeButton.Importance = widget.HighImportance
-	
+
+	ui.CancelButton = widget.NewButton("Cancel", nil)
+	ui.CancelButton.SetIcon(theme.CancelIcon())
+	ui.CancelButton.Disable()
+
 	ui.ConfigButton = widget.NewButton("Configuration", nil)
 	ui.ConfigButton.SetIcon(theme.SettingsIcon())
-	
+
 	ui.SaveButton = widget.NewButton("Save Results", nil)
 	ui.SaveButton.SetIcon(theme.DocumentSaveIcon())
 	ui.SaveButton.Disable()
-	
+
 	ui.ClearButton = widget.NewButton("Clear", nil)
 	ui.ClearButton.SetIcon(theme.DeleteIcon())
-	
+
 	// Progress and status
 	ui.ProgressBar = widget.NewProgressBar()
 	ui.ProgressBar.Hide()
-	
+
 	ui.StatusLabel = widget.NewLabel("Ready to analyze GitHub profiles")
-	
+
 	// Results display - Use RichText for better dark theme visibility
 	ui.ResultsRichText = widget.NewRichTextFromMarkdown("Analysis results will appear here...")
 	ui.ResultsRichText.Wrapping = fyne.TextWrapWord
@@ -120,6 +125,8 @@ func (ui *MainWindowUI) createButtonPanel() *fyne.Container {
 		layout.NewSpacer(),
 		ui.AnalyzeButton,
 		FixedSpacer(5, 5, 0, 0),
+		ui.CancelButton,
+		FixedSpacer(5, 5, 0, 0),
 		ui.ConfigButton,
 	)
 
@@ -249,6 +256,11 @@ func (ui *MainWindowUI) SetAnalyzeButtonCallback(callback func()) {
 	ui.AnalyzeButton.OnTapped = callback
 }
 
+// SetCancelButtonCallback sets the callback for the cancel button
+func (ui *MainWindowUI) SetCancelButtonCallback(callback func()) {
+	ui.CancelButton.OnTapped = callback
+}
+
 // SetConfigButtonCallback sets the callback for the config button
 func (ui *MainWindowUI) SetConfigButtonCallback(callback func()) {
 	ui.ConfigButton.OnTapped = callback
@@ -323,3 +335,14 @@ func (ui *MainWindowUI) SetAnalyzeButtonEnabled(enabled bool) {
 		}
 	})
 }
+
+// SetCancelButtonEnabled enables/disables the cancel button
+func (ui *MainWindowUI) SetCancelButtonEnabled(enabled bool) {
+	fyne.Do(func() {
+		if enabled {
+			ui.CancelButton.Enable()
+		} else {
+			ui.CancelButton.Disable()
+		}
+	})
+}
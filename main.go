@@ -6,10 +6,16 @@ import (
 	"os"
 
 	"dev_profiler/internal/app"
+	"dev_profiler/internal/cli"
 	"dev_profiler/internal/utils"
 )
 
 func main() {
+	// Run a headless audit without the GUI if requested
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(cli.RunAudit(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Parse command line flags
 	showVersion := flag.Bool("version", false, "Show version information")
 	flag.Parse()